@@ -5,10 +5,9 @@ import (
 	"fmt"
 
 	"connectrpc.com/connect"
-	"github.com/common-fate/sdk/factory/service/deployment"
-	"github.com/common-fate/sdk/factoryconfig"
 	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
 	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -20,6 +19,7 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &AWSACMCertificateResource{}
+var _ resource.ResourceWithModifyPlan = &AWSACMCertificateResource{}
 var _ resource.ResourceWithImportState = &AWSACMCertificateResource{}
 
 func NewAWSACMCertificateResource() resource.Resource {
@@ -28,7 +28,8 @@ func NewAWSACMCertificateResource() resource.Resource {
 
 // AWSACMCertificateResource defines the resource implementation.
 type AWSACMCertificateResource struct {
-	client deploymentv1alpha1connect.DeploymentServiceClient
+	client  deploymentv1alpha1connect.DeploymentServiceClient
+	factory *deploymeta.Client
 }
 
 type AWSACMCertificateResourceModel struct {
@@ -86,21 +87,29 @@ func (r *AWSACMCertificateResource) Configure(ctx context.Context, req resource.
 		return
 	}
 
-	cfg, ok := req.ProviderData.(*factoryconfig.Context)
+	client, ok := req.ProviderData.(*deploymeta.Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *factoryconfig.Context, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *deploymeta.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = deployment.NewFromConfig(cfg)
+	r.client = client.Deployment()
+	r.factory = client
+}
+
+func (r *AWSACMCertificateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	requireFeatures(ctx, r.factory, req, &resp.Diagnostics, "deploymeta_aws_acm_certificate", deploymeta.FeatureAWSACMCertificates)
 }
 
 func (r *AWSACMCertificateResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data AWSACMCertificateResourceModel
 
 	// Read Terraform plan data into the model
@@ -133,6 +142,9 @@ func (r *AWSACMCertificateResource) Create(ctx context.Context, req resource.Cre
 }
 
 func (r *AWSACMCertificateResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data AWSACMCertificateResourceModel
 
 	// Read Terraform prior state data into the model
@@ -163,6 +175,9 @@ func (r *AWSACMCertificateResource) Read(ctx context.Context, req resource.ReadR
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 func (r *AWSACMCertificateResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data AWSACMCertificateResourceModel
 
 	// Read Terraform plan data into the model
@@ -198,6 +213,9 @@ func (r *AWSACMCertificateResource) Update(ctx context.Context, req resource.Upd
 }
 
 func (r *AWSACMCertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data AWSACMCertificateResourceModel
 
 	// Read Terraform plan data into the model
@@ -0,0 +1,338 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"connectrpc.com/connect"
+	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
+	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/protobuf/proto"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &ConfigFreezeChecksumResource{}
+var _ resource.ResourceWithModifyPlan = &ConfigFreezeChecksumResource{}
+
+func NewConfigFreezeChecksumResource() resource.Resource {
+	return &ConfigFreezeChecksumResource{}
+}
+
+// ConfigFreezeChecksumResource defines the resource implementation.
+type ConfigFreezeChecksumResource struct {
+	client  deploymentv1alpha1connect.DeploymentServiceClient
+	factory *deploymeta.Client
+}
+
+// ConfigFreezeChecksumResourceModel describes the resource data model.
+type ConfigFreezeChecksumResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	DNSRecordIDs     types.Set    `tfsdk:"dns_record_ids"`
+	CertificateIDs   types.Set    `tfsdk:"certificate_ids"`
+	Triggers         types.Map    `tfsdk:"triggers"`
+	Checksum         types.String `tfsdk:"checksum"`
+	ObservedChecksum types.String `tfsdk:"observed_checksum"`
+}
+
+func (r *ConfigFreezeChecksumResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_config_freeze_checksum"
+}
+
+func (r *ConfigFreezeChecksumResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Records a checksum of the metadata registered for a Common Fate deployment, and fails subsequent plans if the registered metadata changes without a change to this resource's arguments. Set `triggers` from the values of the resources which register the metadata, so that changes made through Terraform record a new checksum.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The deployment ID",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"dns_record_ids": schema.SetAttribute{
+				MarkdownDescription: "The IDs of DNS records to include in the checksum",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"certificate_ids": schema.SetAttribute{
+				MarkdownDescription: "The IDs of AWS ACM certificates to include in the checksum",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"triggers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values which cause a new checksum to be recorded when changed",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"checksum": schema.StringAttribute{
+				MarkdownDescription: "The checksum of the registered metadata, recorded when the resource was last created or updated",
+				Computed:            true,
+			},
+			"observed_checksum": schema.StringAttribute{
+				MarkdownDescription: "The checksum of the registered metadata when it was last read",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *ConfigFreezeChecksumResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*deploymeta.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *deploymeta.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client.Deployment()
+	r.factory = client
+}
+
+func (r *ConfigFreezeChecksumResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check when destroying the resource.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan, state ConfigFreezeChecksumResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	features := []deploymeta.Feature{deploymeta.FeatureDeployment, deploymeta.FeatureTerraformOutput}
+
+	// DNS records and certificates are only read if their IDs are set.
+	if plan.DNSRecordIDs.IsUnknown() || len(plan.DNSRecordIDs.Elements()) > 0 {
+		features = append(features, deploymeta.FeatureDNSRecords)
+	}
+
+	if plan.CertificateIDs.IsUnknown() || len(plan.CertificateIDs.Elements()) > 0 {
+		features = append(features, deploymeta.FeatureAWSACMCertificates)
+	}
+
+	requireFeatures(ctx, r.factory, req, &resp.Diagnostics, "deploymeta_config_freeze_checksum", features...)
+
+	// Nothing to compare when creating the resource.
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configChanged := !plan.Triggers.Equal(state.Triggers) ||
+		!plan.DNSRecordIDs.Equal(state.DNSRecordIDs) ||
+		!plan.CertificateIDs.Equal(state.CertificateIDs)
+
+	if !configChanged && !state.ObservedChecksum.Equal(state.Checksum) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("checksum"),
+			"Registered metadata changed outside of Terraform",
+			fmt.Sprintf("The metadata registered for deployment %s no longer matches the recorded checksum (recorded %s, observed %s), and no change to this resource's arguments accounts for it. Investigate the change, then update triggers to record a new checksum.", state.ID.ValueString(), state.Checksum.ValueString(), state.ObservedChecksum.ValueString()),
+		)
+	}
+}
+
+func (r *ConfigFreezeChecksumResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
+	var data ConfigFreezeChecksumResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.record(ctx, &data, resp.Diagnostics.AddError)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "recorded registered metadata checksum")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConfigFreezeChecksumResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
+	var data ConfigFreezeChecksumResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, checksum, err := r.checksum(ctx, data)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Common Fate registered metadata, got error: %s", err))
+		return
+	}
+
+	data.ID = types.StringValue(id)
+	data.ObservedChecksum = types.StringValue(checksum)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConfigFreezeChecksumResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
+	var data ConfigFreezeChecksumResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.record(ctx, &data, resp.Diagnostics.AddError)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "recorded registered metadata checksum")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ConfigFreezeChecksumResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// no-op: the checksum is only stored in Terraform state.
+}
+
+// record computes the checksum of the registered metadata and stores it
+// as both the recorded and observed checksum.
+func (r *ConfigFreezeChecksumResource) record(ctx context.Context, data *ConfigFreezeChecksumResourceModel, addError func(summary, detail string)) {
+	id, checksum, err := r.checksum(ctx, *data)
+	if err != nil {
+		addError("Client Error", fmt.Sprintf("Unable to read Common Fate registered metadata, got error: %s", err))
+		return
+	}
+
+	data.ID = types.StringValue(id)
+	data.Checksum = types.StringValue(checksum)
+	data.ObservedChecksum = types.StringValue(checksum)
+}
+
+// checksum returns the deployment ID and a SHA256 checksum of the deployment
+// metadata, the Terraform outputs and the DNS records and certificates
+// referenced by the model. Records and certificates which no longer exist
+// contribute their absence to the checksum.
+func (r *ConfigFreezeChecksumResource) checksum(ctx context.Context, data ConfigFreezeChecksumResourceModel) (string, string, error) {
+	var dnsRecordIDs, certificateIDs []string
+
+	if diags := data.DNSRecordIDs.ElementsAs(ctx, &dnsRecordIDs, false); diags.HasError() {
+		return "", "", fmt.Errorf("reading dns_record_ids: %v", diags)
+	}
+
+	if diags := data.CertificateIDs.ElementsAs(ctx, &certificateIDs, false); diags.HasError() {
+		return "", "", fmt.Errorf("reading certificate_ids: %v", diags)
+	}
+
+	sort.Strings(dnsRecordIDs)
+	sort.Strings(certificateIDs)
+
+	h := sha256.New()
+
+	write := func(name string, msg proto.Message) error {
+		b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(h, "%s:%d:", name, len(b))
+		h.Write(b)
+		return nil
+	}
+
+	deploymentRes, err := r.client.GetDeployment(ctx, connect.NewRequest(&deploymentv1alpha1.GetDeploymentRequest{}))
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := write("deployment", deploymentRes.Msg.Deployment); err != nil {
+		return "", "", err
+	}
+
+	outputRes, err := r.client.GetTerraformOutput(ctx, connect.NewRequest(&deploymentv1alpha1.GetTerraformOutputRequest{}))
+	if connect.CodeOf(err) == connect.CodeNotFound {
+		fmt.Fprint(h, "terraform_output:missing:")
+	} else if err != nil {
+		return "", "", err
+	} else if err := write("terraform_output", outputRes.Msg.Output); err != nil {
+		return "", "", err
+	}
+
+	for _, id := range dnsRecordIDs {
+		res, err := r.client.GetDNSRecord(ctx, connect.NewRequest(&deploymentv1alpha1.GetDNSRecordRequest{
+			Id: id,
+		}))
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			fmt.Fprintf(h, "dns_record/%s:missing:", id)
+			continue
+		} else if err != nil {
+			return "", "", err
+		}
+
+		if err := write("dns_record/"+id, res.Msg.Record); err != nil {
+			return "", "", err
+		}
+	}
+
+	for _, id := range certificateIDs {
+		res, err := r.client.GetAWSACMCertificate(ctx, connect.NewRequest(&deploymentv1alpha1.GetAWSACMCertificateRequest{
+			Id: id,
+		}))
+		if connect.CodeOf(err) == connect.CodeNotFound {
+			fmt.Fprintf(h, "certificate/%s:missing:", id)
+			continue
+		} else if err != nil {
+			return "", "", err
+		}
+
+		if err := write("certificate/"+id, res.Msg.Certificate); err != nil {
+			return "", "", err
+		}
+	}
+
+	return deploymentRes.Msg.Deployment.Id, hex.EncodeToString(h.Sum(nil)), nil
+}
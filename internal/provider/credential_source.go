@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+// CredentialSourceModel describes where the provider obtains its licence key
+// if licence_key is not set.
+type CredentialSourceModel struct {
+	Env  types.String               `tfsdk:"env"`
+	File types.String               `tfsdk:"file"`
+	Exec *ExecCredentialSourceModel `tfsdk:"exec"`
+}
+
+// ExecCredentialSourceModel describes a credential helper command.
+type ExecCredentialSourceModel struct {
+	Command types.String `tfsdk:"command"`
+	Args    types.List   `tfsdk:"args"`
+	Env     types.Map    `tfsdk:"env"`
+}
+
+// credentialSourceAttrTypes and execCredentialSourceAttrTypes are the
+// attribute types of the credential_source and credential_source.exec
+// objects.
+var (
+	credentialSourceAttrTypes = map[string]attr.Type{
+		"env":  types.StringType,
+		"file": types.StringType,
+		"exec": types.ObjectType{AttrTypes: execCredentialSourceAttrTypes},
+	}
+
+	execCredentialSourceAttrTypes = map[string]attr.Type{
+		"command": types.StringType,
+		"args":    types.ListType{ElemType: types.StringType},
+		"env":     types.MapType{ElemType: types.StringType},
+	}
+)
+
+// credentialSource converts a credential_source object into a
+// deploymeta.CredentialSource. The object must be known, and exactly one of
+// env, file and exec must be set.
+func credentialSource(ctx context.Context, obj types.Object) (deploymeta.CredentialSource, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var sources []deploymeta.CredentialSource
+
+	var m CredentialSourceModel
+
+	diags.Append(obj.As(ctx, &m, basetypes.ObjectAsOptions{})...)
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if !m.Env.IsNull() {
+		sources = append(sources, deploymeta.EnvCredentials(m.Env.ValueString()))
+	}
+
+	if !m.File.IsNull() {
+		sources = append(sources, deploymeta.FileCredentials(m.File.ValueString()))
+	}
+
+	if m.Exec != nil {
+		// command is required by the schema, but not when credential_source
+		// is provided in config_json.
+		if m.Exec.Command.ValueString() == "" {
+			diags.AddAttributeError(path.Root("credential_source").AtName("exec").AtName("command"), "Invalid credential source", "The command of an exec credential source must be set.")
+			return nil, diags
+		}
+
+		helper := deploymeta.ExecCredentials{
+			Command: m.Exec.Command.ValueString(),
+		}
+
+		diags.Append(m.Exec.Args.ElementsAs(ctx, &helper.Args, false)...)
+		diags.Append(m.Exec.Env.ElementsAs(ctx, &helper.Env, false)...)
+
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		sources = append(sources, helper)
+	}
+
+	if len(sources) != 1 {
+		diags.AddAttributeError(path.Root("credential_source"), "Invalid credential source", "Exactly one of env, file or exec must be set in credential_source.")
+		return nil, diags
+	}
+
+	return sources[0], diags
+}
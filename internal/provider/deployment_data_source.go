@@ -5,10 +5,9 @@ import (
 	"fmt"
 
 	"connectrpc.com/connect"
-	"github.com/common-fate/sdk/factory/service/deployment"
-	"github.com/common-fate/sdk/factoryconfig"
 	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
 	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -31,6 +30,8 @@ type DeploymentDataSource struct {
 type DeploymentDataSourceModel struct {
 	Id               types.String `tfsdk:"id"`
 	DefaultAppDomain types.String `tfsdk:"default_app_domain"`
+	DefaultSubdomain types.String `tfsdk:"default_subdomain"`
+	DNSZoneName      types.String `tfsdk:"dns_zone_name"`
 }
 
 func (d *DeploymentDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -50,6 +51,14 @@ func (d *DeploymentDataSource) Schema(ctx context.Context, req datasource.Schema
 				MarkdownDescription: "The default app domain for the deployment",
 				Computed:            true,
 			},
+			"default_subdomain": schema.StringAttribute{
+				MarkdownDescription: "The default DNS subdomain for the deployment",
+				Computed:            true,
+			},
+			"dns_zone_name": schema.StringAttribute{
+				MarkdownDescription: "The base DNS name for the deployment, for example 'commonfate.app'",
+				Computed:            true,
+			},
 		},
 	}
 }
@@ -60,21 +69,24 @@ func (d *DeploymentDataSource) Configure(ctx context.Context, req datasource.Con
 		return
 	}
 
-	cfg, ok := req.ProviderData.(*factoryconfig.Context)
+	client, ok := req.ProviderData.(*deploymeta.Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *factoryconfig.Context, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *deploymeta.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	d.client = deployment.NewFromConfig(cfg)
+	d.client = client.Deployment()
 }
 
 func (d *DeploymentDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data DeploymentDataSourceModel
 
 	// Read Terraform configuration data into the model
@@ -92,6 +104,8 @@ func (d *DeploymentDataSource) Read(ctx context.Context, req datasource.ReadRequ
 
 	data.Id = types.StringValue(apiRes.Msg.Deployment.Id)
 	data.DefaultAppDomain = types.StringValue(apiRes.Msg.Deployment.DefaultAppDomain)
+	data.DefaultSubdomain = types.StringValue(apiRes.Msg.Deployment.DefaultSubdomain)
+	data.DNSZoneName = types.StringValue(apiRes.Msg.Deployment.DnsZoneName)
 
 	tflog.Trace(ctx, "read deployment metadata")
 
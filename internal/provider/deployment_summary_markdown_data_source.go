@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"connectrpc.com/connect"
+	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
+	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &DeploymentSummaryMarkdownDataSource{}
+
+func NewDeploymentSummaryMarkdownDataSource() datasource.DataSource {
+	return &DeploymentSummaryMarkdownDataSource{}
+}
+
+// DeploymentSummaryMarkdownDataSource defines the data source implementation.
+type DeploymentSummaryMarkdownDataSource struct {
+	client deploymentv1alpha1connect.DeploymentServiceClient
+}
+
+// DeploymentSummaryMarkdownDataSourceModel describes the data source data model.
+type DeploymentSummaryMarkdownDataSourceModel struct {
+	Id             types.String `tfsdk:"id"`
+	DNSRecordIDs   types.Set    `tfsdk:"dns_record_ids"`
+	CertificateIDs types.Set    `tfsdk:"certificate_ids"`
+	Markdown       types.String `tfsdk:"markdown"`
+}
+
+func (d *DeploymentSummaryMarkdownDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_deployment_summary_markdown"
+}
+
+func (d *DeploymentSummaryMarkdownDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Renders a Markdown summary of the metadata registered for the current Common Fate deployment.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The deployment ID",
+				Computed:            true,
+			},
+			"dns_record_ids": schema.SetAttribute{
+				MarkdownDescription: "The IDs of DNS records to include in the summary",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"certificate_ids": schema.SetAttribute{
+				MarkdownDescription: "The IDs of AWS ACM certificates to include in the summary",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"markdown": schema.StringAttribute{
+				MarkdownDescription: "The rendered Markdown summary",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *DeploymentSummaryMarkdownDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*deploymeta.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *deploymeta.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client.Deployment()
+}
+
+func (d *DeploymentSummaryMarkdownDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
+	var data DeploymentSummaryMarkdownDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var dnsRecordIDs, certificateIDs []string
+
+	resp.Diagnostics.Append(data.DNSRecordIDs.ElementsAs(ctx, &dnsRecordIDs, false)...)
+	resp.Diagnostics.Append(data.CertificateIDs.ElementsAs(ctx, &certificateIDs, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	sort.Strings(dnsRecordIDs)
+	sort.Strings(certificateIDs)
+
+	deploymentRes, err := d.client.GetDeployment(ctx, connect.NewRequest(&deploymentv1alpha1.GetDeploymentRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Common Fate deployment metadata, got error: %s", err))
+		return
+	}
+
+	dep := deploymentRes.Msg.Deployment
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Common Fate deployment %s\n\n", markdownCode(dep.Id))
+	b.WriteString("## Domains\n\n")
+	fmt.Fprintf(&b, "- App domain: %s\n", markdownCode(dep.DefaultAppDomain))
+	fmt.Fprintf(&b, "- DNS zone: %s\n", markdownCode(dep.DnsZoneName))
+
+	outputRes, err := d.client.GetTerraformOutput(ctx, connect.NewRequest(&deploymentv1alpha1.GetTerraformOutputRequest{}))
+	if err != nil && connect.CodeOf(err) != connect.CodeNotFound {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Common Fate Terraform outputs, got error: %s", err))
+		return
+	}
+
+	b.WriteString("\n## Terraform outputs\n\n")
+
+	if err != nil {
+		b.WriteString("No Terraform outputs have been registered.\n")
+	} else {
+		o := outputRes.Msg.GetOutput()
+
+		b.WriteString("| Output | Value |\n| --- | --- |\n")
+		writeMarkdownRow(&b, "SAML SSO ACS URL", o.GetSamlSsoAcsUrl())
+		writeMarkdownRow(&b, "SAML SSO Entity ID", o.GetSamlSsoEntityId())
+		writeMarkdownRow(&b, "Cognito user pool ID", o.GetCognitoUserPoolId())
+		writeMarkdownRow(&b, "DNS CNAME record for app domain", o.GetDnsCnameRecordForAppDomain())
+		writeMarkdownRow(&b, "DNS CNAME record for auth domain", o.GetDnsCnameRecordForAuthDomain())
+		writeMarkdownRow(&b, "Web client ID", o.GetWebClientId())
+		writeMarkdownRow(&b, "CLI client ID", o.GetCliClientId())
+		writeMarkdownRow(&b, "Terraform client ID", o.GetTerraformClientId())
+		writeMarkdownRow(&b, "Read-Only client ID", o.GetReadOnlyClientId())
+		writeMarkdownRow(&b, "Provisioner client ID", o.GetProvisionerClientId())
+		writeMarkdownRow(&b, "VPC ID", o.GetVpcId())
+	}
+
+	if len(dnsRecordIDs) > 0 {
+		b.WriteString("\n## DNS records\n\n")
+		b.WriteString("| Name | Zone | Type | Values |\n| --- | --- | --- | --- |\n")
+
+		for _, id := range dnsRecordIDs {
+			res, err := d.client.GetDNSRecord(ctx, connect.NewRequest(&deploymentv1alpha1.GetDNSRecordRequest{
+				Id: id,
+			}))
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Common Fate DNS record %s, got error: %s", id, err))
+				return
+			}
+
+			r := res.Msg.Record
+
+			var values []string
+			for _, v := range r.Values {
+				values = append(values, markdownCode(v))
+			}
+
+			writeMarkdownTableRow(&b, markdownCode(r.Name), markdownCode(r.DnsZoneName), deploymeta.DNSRecordTypeString(r.Type), strings.Join(values, ", "))
+		}
+	}
+
+	if len(certificateIDs) > 0 {
+		b.WriteString("\n## Certificates\n\n")
+		b.WriteString("| Domain | Status | ARN |\n| --- | --- | --- |\n")
+
+		for _, id := range certificateIDs {
+			res, err := d.client.GetAWSACMCertificate(ctx, connect.NewRequest(&deploymentv1alpha1.GetAWSACMCertificateRequest{
+				Id: id,
+			}))
+			if err != nil {
+				resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Common Fate AWS ACM certificate %s, got error: %s", id, err))
+				return
+			}
+
+			c := res.Msg.Certificate
+			writeMarkdownTableRow(&b, markdownCode(c.DomainName), c.Status, markdownCode(c.Arn))
+		}
+	}
+
+	data.Id = types.StringValue(dep.Id)
+	data.Markdown = types.StringValue(b.String())
+
+	tflog.Trace(ctx, "rendered deployment summary")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func writeMarkdownRow(b *strings.Builder, name, value string) {
+	writeMarkdownTableRow(b, name, markdownCode(value))
+}
+
+// markdownCellReplacer escapes the characters which would end a Markdown
+// table cell or row.
+var markdownCellReplacer = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ", "\r", " ")
+
+// writeMarkdownTableRow writes a Markdown table row containing the cells,
+// escaping any characters which would break the table.
+func writeMarkdownTableRow(b *strings.Builder, cells ...string) {
+	b.WriteString("|")
+
+	for _, cell := range cells {
+		fmt.Fprintf(b, " %s |", markdownCellReplacer.Replace(cell))
+	}
+
+	b.WriteString("\n")
+}
+
+// markdownCode returns the value as a Markdown code span, fenced with more
+// backticks than the longest run of backticks in the value.
+func markdownCode(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	fence := "`"
+	for strings.Contains(value, fence) {
+		fence += "`"
+	}
+
+	// A single space is stripped from either end of the span, so pad values
+	// which would otherwise merge with the fence or lose a space.
+	if strings.HasPrefix(value, "`") || strings.HasSuffix(value, "`") || strings.HasPrefix(value, " ") || strings.HasSuffix(value, " ") {
+		value = " " + value + " "
+	}
+
+	return fence + value + fence
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownCode(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "", want: ""},
+		{value: "example.com", want: "`example.com`"},
+		{value: "a`b", want: "``a`b``"},
+		{value: "a``b`c", want: "```a``b`c```"},
+		{value: "`quoted`", want: "`` `quoted` ``"},
+		{value: " padded ", want: "`  padded  `"},
+	}
+
+	for _, tt := range tests {
+		if got := markdownCode(tt.value); got != tt.want {
+			t.Errorf("markdownCode(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWriteMarkdownTableRow(t *testing.T) {
+	var b strings.Builder
+
+	writeMarkdownTableRow(&b, markdownCode("v=spf1 | -all"), "line\nbreak")
+
+	want := "| `v=spf1 \\| -all` | line break |\n"
+	if got := b.String(); got != want {
+		t.Errorf("writeMarkdownTableRow() = %q, want %q", got, want)
+	}
+}
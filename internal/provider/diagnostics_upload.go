@@ -0,0 +1,253 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+	accessv1alpha1 "github.com/common-fate/sdk/gen/commonfate/access/v1alpha1"
+	supportv1alpha1 "github.com/common-fate/sdk/gen/commonfate/control/support/v1alpha1"
+	cloudsupportv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/cloudsupport/v1alpha1"
+	"github.com/common-fate/sdk/gen/commonfate/factory/cloudsupport/v1alpha1/cloudsupportv1alpha1connect"
+	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
+	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ resource.Resource = &DiagnosticsUploadResource{}
+var _ resource.ResourceWithModifyPlan = &DiagnosticsUploadResource{}
+
+func NewDiagnosticsUploadResource() resource.Resource {
+	return &DiagnosticsUploadResource{}
+}
+
+// DiagnosticsUploadResource defines the resource implementation.
+type DiagnosticsUploadResource struct {
+	client  deploymentv1alpha1connect.DeploymentServiceClient
+	support cloudsupportv1alpha1connect.CloudSupportServiceClient
+	factory *deploymeta.Client
+}
+
+// DiagnosticsUploadResourceModel describes the resource data model.
+type DiagnosticsUploadResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	Trigger      types.String `tfsdk:"trigger"`
+	Metadata     types.Map    `tfsdk:"metadata"`
+	ContactName  types.String `tfsdk:"contact_name"`
+	ContactEmail types.String `tfsdk:"contact_email"`
+	FileName     types.String `tfsdk:"file_name"`
+}
+
+// diagnosticsBundle is the JSON document uploaded to the Factory.
+type diagnosticsBundle struct {
+	GeneratedAt     time.Time         `json:"generated_at"`
+	Deployment      json.RawMessage   `json:"deployment,omitempty"`
+	TerraformOutput json.RawMessage   `json:"terraform_output,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+}
+
+func (r *DiagnosticsUploadResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_diagnostics_upload"
+}
+
+func (r *DiagnosticsUploadResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Uploads a diagnostics bundle containing the deployment's registered metadata to Common Fate support. A new bundle is uploaded whenever any argument changes.",
+
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The attachment ID of the uploaded bundle. Reference this ID when opening a support ticket.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"trigger": schema.StringAttribute{
+				MarkdownDescription: "An arbitrary value which causes a new bundle to be uploaded when changed",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"metadata": schema.MapAttribute{
+				MarkdownDescription: "Additional metadata to include in the bundle, such as values from other resources in state",
+				Optional:            true,
+				ElementType:         types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"contact_name": schema.StringAttribute{
+				MarkdownDescription: "The name of the person support should contact about the bundle",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"contact_email": schema.StringAttribute{
+				MarkdownDescription: "The email address of the person support should contact about the bundle",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"file_name": schema.StringAttribute{
+				MarkdownDescription: "The file name of the uploaded bundle",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *DiagnosticsUploadResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*deploymeta.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *deploymeta.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client.Deployment()
+	r.support = client.CloudSupport()
+	r.factory = client
+}
+
+func (r *DiagnosticsUploadResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	requireFeatures(ctx, r.factory, req, &resp.Diagnostics, "deploymeta_diagnostics_upload", deploymeta.FeatureDeployment, deploymeta.FeatureTerraformOutput, deploymeta.FeatureCloudSupport)
+}
+
+func (r *DiagnosticsUploadResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
+	var data DiagnosticsUploadResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	bundle := diagnosticsBundle{
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	resp.Diagnostics.Append(data.Metadata.ElementsAs(ctx, &bundle.Metadata, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deploymentRes, err := r.client.GetDeployment(ctx, connect.NewRequest(&deploymentv1alpha1.GetDeploymentRequest{}))
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Common Fate deployment metadata, got error: %s", err))
+		return
+	}
+
+	bundle.Deployment, err = protojson.Marshal(deploymentRes.Msg.Deployment)
+	if err != nil {
+		resp.Diagnostics.AddError("Error building diagnostics bundle", err.Error())
+		return
+	}
+
+	// Terraform outputs may not have been registered yet, in which case
+	// the bundle is still useful without them.
+	outputRes, err := r.client.GetTerraformOutput(ctx, connect.NewRequest(&deploymentv1alpha1.GetTerraformOutputRequest{}))
+	if err != nil && connect.CodeOf(err) != connect.CodeNotFound {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Common Fate Terraform outputs, got error: %s", err))
+		return
+	}
+
+	if err == nil {
+		bundle.TerraformOutput, err = protojson.Marshal(outputRes.Msg.Output)
+		if err != nil {
+			resp.Diagnostics.AddError("Error building diagnostics bundle", err.Error())
+			return
+		}
+	}
+
+	content, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		resp.Diagnostics.AddError("Error building diagnostics bundle", err.Error())
+		return
+	}
+
+	fileName := fmt.Sprintf("deploymeta-diagnostics-%s.json", bundle.GeneratedAt.Format("20060102T150405Z"))
+
+	res, err := r.support.CreateAttachment(ctx, connect.NewRequest(&cloudsupportv1alpha1.CreateAttachmentRequest{
+		AttachmentInput: &supportv1alpha1.AttachmentInput{
+			Name:      fileName,
+			SizeBytes: uint32(len(content)),
+		},
+		User: &accessv1alpha1.User{
+			Name:  data.ContactName.ValueString(),
+			Email: data.ContactEmail.ValueString(),
+		},
+	}))
+	if err != nil {
+		resp.Diagnostics.AddError("Common Fate Deployment API error", fmt.Sprintf("Unable to create a diagnostics attachment for the deployment, got error: %s", err.Error()))
+		return
+	}
+
+	err = r.factory.UploadAttachment(ctx, res.Msg.Attachment, content)
+	if err != nil {
+		resp.Diagnostics.AddError("Common Fate Deployment API error", fmt.Sprintf("Unable to upload the diagnostics bundle for the deployment, got error: %s", err.Error()))
+		return
+	}
+
+	tflog.Trace(ctx, "uploaded diagnostics bundle")
+
+	// Convert from the API data model to the Terraform data model
+	// and set any unknown attribute values.
+	data.ID = types.StringValue(res.Msg.Attachment.Id)
+	data.FileName = types.StringValue(res.Msg.Attachment.FileName)
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DiagnosticsUploadResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	// Uploaded attachments can't be read back from the Factory,
+	// so the prior state is kept as-is.
+}
+
+func (r *DiagnosticsUploadResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data DiagnosticsUploadResourceModel
+
+	// Every argument requires replacement, so there is nothing to update.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DiagnosticsUploadResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	// no-op: uploaded attachments are retained by Common Fate support.
+}
@@ -8,19 +8,21 @@ import (
 	"fmt"
 
 	"connectrpc.com/connect"
-	"github.com/common-fate/sdk/factory/service/deployment"
-	"github.com/common-fate/sdk/factoryconfig"
 	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
 	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &DNSRecordResource{}
+var _ resource.ResourceWithModifyPlan = &DNSRecordResource{}
 var _ resource.ResourceWithImportState = &DNSRecordResource{}
 
 func NewDNSRecordResource() resource.Resource {
@@ -29,7 +31,8 @@ func NewDNSRecordResource() resource.Resource {
 
 // DNSRecordResource defines the resource implementation.
 type DNSRecordResource struct {
-	client deploymentv1alpha1connect.DeploymentServiceClient
+	client  deploymentv1alpha1connect.DeploymentServiceClient
+	factory *deploymeta.Client
 }
 
 // DNSRecordResourceModel describes the resource data model.
@@ -39,6 +42,7 @@ type DNSRecordResourceModel struct {
 	Type     types.String `tfsdk:"type"`
 	ZoneName types.String `tfsdk:"zone_name"`
 	Values   types.Set    `tfsdk:"values"`
+	FQDN     types.String `tfsdk:"fqdn"`
 }
 
 func (r *DNSRecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -55,22 +59,38 @@ func (r *DNSRecordResource) Schema(ctx context.Context, req resource.SchemaReque
 				Computed:            true,
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "The DNS record name",
+				MarkdownDescription: "The DNS record name. Changing this creates a new DNS record.",
 				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"zone_name": schema.StringAttribute{
-				MarkdownDescription: "The DNS zone name",
+				MarkdownDescription: "The DNS zone name. Changing this creates a new DNS record.",
 				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"type": schema.StringAttribute{
-				MarkdownDescription: "The DNS record type. Must be one of ['TXT', 'CNAME']",
+				MarkdownDescription: "The DNS record type. Must be one of ['TXT', 'CNAME']. Changing this creates a new DNS record.",
 				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"values": schema.SetAttribute{
 				MarkdownDescription: "The DNS record values",
 				Required:            true,
 				ElementType:         types.StringType,
 			},
+			"fqdn": schema.StringAttribute{
+				MarkdownDescription: "The fully-qualified domain name of the DNS record",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
@@ -81,21 +101,29 @@ func (r *DNSRecordResource) Configure(ctx context.Context, req resource.Configur
 		return
 	}
 
-	cfg, ok := req.ProviderData.(*factoryconfig.Context)
+	client, ok := req.ProviderData.(*deploymeta.Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *factoryconfig.Context, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *deploymeta.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = deployment.NewFromConfig(cfg)
+	r.client = client.Deployment()
+	r.factory = client
+}
+
+func (r *DNSRecordResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	requireFeatures(ctx, r.factory, req, &resp.Diagnostics, "deploymeta_dns_record", deploymeta.FeatureDNSRecords)
 }
 
 func (r *DNSRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data DNSRecordResourceModel
 
 	// Read Terraform plan data into the model
@@ -113,15 +141,9 @@ func (r *DNSRecordResource) Create(ctx context.Context, req resource.CreateReque
 		return
 	}
 
-	var rrType deploymentv1alpha1.DNSRecordType
-
-	switch data.Type.ValueString() {
-	case "TXT":
-		rrType = deploymentv1alpha1.DNSRecordType_DNS_RECORD_TYPE_TXT
-	case "CNAME":
-		rrType = deploymentv1alpha1.DNSRecordType_DNS_RECORD_TYPE_CNAME
-	default:
-		resp.Diagnostics.AddError("Invalid DNS record type", fmt.Sprintf("the DNS record type '%s' is invalid. Valid values are ['TXT', 'CNAME']", data.Type.ValueString()))
+	rrType, err := deploymeta.ParseDNSRecordType(data.Type.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid DNS record type", err.Error())
 		return
 	}
 
@@ -141,12 +163,16 @@ func (r *DNSRecordResource) Create(ctx context.Context, req resource.CreateReque
 	// Convert from the API data model to the Terraform data model
 	// and set any unknown attribute values.
 	data.ID = types.StringValue(res.Msg.Created.Id)
+	data.FQDN = types.StringValue(deploymeta.DNSRecordFQDN(data.Name.ValueString(), data.ZoneName.ValueString()))
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *DNSRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data DNSRecordResourceModel
 
 	// Read Terraform prior state data into the model
@@ -168,6 +194,7 @@ func (r *DNSRecordResource) Read(ctx context.Context, req resource.ReadRequest,
 	}
 
 	data.ID = types.StringValue(apiRes.Msg.Record.Id)
+	data.FQDN = types.StringValue(deploymeta.DNSRecordFQDN(apiRes.Msg.Record.Name, apiRes.Msg.Record.DnsZoneName))
 
 	values, diags := types.SetValueFrom(ctx, types.StringType, apiRes.Msg.Record.Values)
 	resp.Diagnostics.Append(diags...)
@@ -182,6 +209,9 @@ func (r *DNSRecordResource) Read(ctx context.Context, req resource.ReadRequest,
 }
 
 func (r *DNSRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data DNSRecordResourceModel
 
 	// Read Terraform plan data into the model
@@ -213,12 +243,16 @@ func (r *DNSRecordResource) Update(ctx context.Context, req resource.UpdateReque
 	// Convert from the API data model to the Terraform data model
 	// and set any unknown attribute values.
 	data.ID = types.StringValue(res.Msg.Updated.Id)
+	data.FQDN = types.StringValue(deploymeta.DNSRecordFQDN(data.Name.ValueString(), data.ZoneName.ValueString()))
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *DNSRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data DNSRecordResourceModel
 
 	// Read Terraform plan data into the model
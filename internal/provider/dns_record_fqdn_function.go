@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ function.Function = &DNSRecordFQDNFunction{}
+
+func NewDNSRecordFQDNFunction() function.Function {
+	return &DNSRecordFQDNFunction{}
+}
+
+// DNSRecordFQDNFunction defines the function implementation.
+type DNSRecordFQDNFunction struct{}
+
+func (f *DNSRecordFQDNFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "dns_record_fqdn"
+}
+
+func (f *DNSRecordFQDNFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Joins a DNS record name and zone name into a fully-qualified domain name",
+		MarkdownDescription: "Joins a DNS record name and zone name into the canonical fully-qualified domain name used by the Common Fate Factory: lowercase and without a trailing dot. An empty name or `@` refers to the zone apex, and names which already end with the zone name are returned unchanged.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "name",
+				MarkdownDescription: "The DNS record name, for example 'console'",
+			},
+			function.StringParameter{
+				Name:                "zone_name",
+				MarkdownDescription: "The DNS zone name, for example 'example.com'",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *DNSRecordFQDNFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var name, zoneName string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &name, &zoneName))
+
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, deploymeta.DNSRecordFQDN(name, zoneName)))
+}
@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// requireFeatures adds an error diagnostic if the plan creates or updates a
+// resource which uses Factory features the deployment doesn't support, so
+// that the problem is reported at plan time rather than partway through an
+// apply. Features whose support is only inferred are reported with a
+// warning instead. If the Factory can't be probed the check is skipped, and
+// any problem is reported by the resource's own requests.
+func requireFeatures(ctx context.Context, client *deploymeta.Client, req resource.ModifyPlanRequest, diags *diag.Diagnostics, resourceType string, features ...deploymeta.Feature) {
+	// The provider isn't configured yet during validation.
+	if client == nil {
+		return
+	}
+
+	// Destroying the resource and no-op plans don't need any features.
+	if req.Plan.Raw.IsNull() || req.Plan.Raw.Equal(req.State.Raw) {
+		return
+	}
+
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(diags)
+
+	for _, feature := range features {
+		supported, err := client.Supports(ctx, feature)
+		if err != nil {
+			tflog.Debug(ctx, "skipping factory feature check", map[string]any{"error": err.Error()})
+			return
+		}
+
+		// A proxy in front of the Factory can make an inferred feature
+		// appear unsupported, so the plan isn't blocked on it.
+		if !supported && feature.Inferred() {
+			diags.AddWarning(
+				"Possibly unsupported by server",
+				fmt.Sprintf("The %s resource requires the '%s' feature, which the Factory at %s doesn't appear to serve. The feature may not be included in the licence, or a proxy in front of the Factory may have rejected the check. If the feature is unavailable, applying the resource will fail.", resourceType, feature, client.Config().BaseURL),
+			)
+
+			continue
+		}
+
+		if !supported {
+			diags.AddError(
+				"Unsupported by server",
+				fmt.Sprintf("The %s resource requires the '%s' feature, which the Factory at %s doesn't support for this deployment. The Factory may be an older or self-hosted build, or the feature may not be included in the licence. Use the deploymeta_provider_capabilities data source to only create the resource when the feature is available.", resourceType, feature, client.Config().BaseURL),
+			)
+		}
+	}
+}
@@ -5,10 +5,9 @@ import (
 	"fmt"
 
 	"connectrpc.com/connect"
-	"github.com/common-fate/sdk/factory/service/deployment"
-	"github.com/common-fate/sdk/factoryconfig"
 	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
 	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -17,6 +16,7 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &TerraformOutputResource{}
+var _ resource.ResourceWithModifyPlan = &TerraformOutputResource{}
 
 func NewTerraformOutputResource() resource.Resource {
 	return &TerraformOutputResource{}
@@ -24,7 +24,8 @@ func NewTerraformOutputResource() resource.Resource {
 
 // TerraformOutputResource defines the resource implementation.
 type TerraformOutputResource struct {
-	client deploymentv1alpha1connect.DeploymentServiceClient
+	client  deploymentv1alpha1connect.DeploymentServiceClient
+	factory *deploymeta.Client
 }
 
 // TerraformOutputResourceModel describes the resource data model.
@@ -105,21 +106,29 @@ func (r *TerraformOutputResource) Configure(ctx context.Context, req resource.Co
 		return
 	}
 
-	cfg, ok := req.ProviderData.(*factoryconfig.Context)
+	client, ok := req.ProviderData.(*deploymeta.Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *factoryconfig.Context, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *deploymeta.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = deployment.NewFromConfig(cfg)
+	r.client = client.Deployment()
+	r.factory = client
+}
+
+func (r *TerraformOutputResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	requireFeatures(ctx, r.factory, req, &resp.Diagnostics, "deploymeta_terraform_output", deploymeta.FeatureTerraformOutput)
 }
 
 func (r *TerraformOutputResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data TerraformOutputResourceModel
 
 	// Read Terraform plan data into the model
@@ -156,6 +165,9 @@ func (r *TerraformOutputResource) Create(ctx context.Context, req resource.Creat
 }
 
 func (r *TerraformOutputResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data TerraformOutputResourceModel
 
 	// Read Terraform prior state data into the model
@@ -190,6 +202,9 @@ func (r *TerraformOutputResource) Read(ctx context.Context, req resource.ReadReq
 }
 
 func (r *TerraformOutputResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
 	var data TerraformOutputResourceModel
 
 	// Read Terraform plan data into the model
@@ -2,14 +2,21 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"slices"
+	"time"
 
-	"github.com/common-fate/sdk/factoryconfig"
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure DeploymentProvider satisfies various provider interfaces.
@@ -26,8 +33,12 @@ type DeploymentProvider struct {
 
 // DeploymentProviderModel describes the provider data model.
 type DeploymentProviderModel struct {
-	BaseURL    types.String `tfsdk:"base_url"`
-	LicenceKey types.String `tfsdk:"licence_key"`
+	BaseURL        types.String `tfsdk:"base_url"`
+	LicenceKey     types.String `tfsdk:"licence_key"`
+	RequestTimeout types.String `tfsdk:"request_timeout"`
+	ConfigJSON     types.String `tfsdk:"config_json"`
+
+	CredentialSource types.Object `tfsdk:"credential_source"`
 }
 
 func (p *DeploymentProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -43,8 +54,51 @@ func (p *DeploymentProvider) Schema(ctx context.Context, req provider.SchemaRequ
 				Optional:            true,
 			},
 			"licence_key": schema.StringAttribute{
-				MarkdownDescription: "The Common Fate licence key.",
-				Required:            true,
+				MarkdownDescription: "The Common Fate licence key. Required unless provided in `config_json` or obtained from `credential_source`.",
+				Optional:            true,
+			},
+			"credential_source": schema.SingleNestedAttribute{
+				MarkdownDescription: "Obtains the licence key from somewhere other than the provider configuration. Exactly one of `env`, `file` or `exec` must be set. Conflicts with `licence_key`.",
+				Optional:            true,
+				Attributes: map[string]schema.Attribute{
+					"env": schema.StringAttribute{
+						MarkdownDescription: "The name of an environment variable containing the licence key.",
+						Optional:            true,
+					},
+					"file": schema.StringAttribute{
+						MarkdownDescription: "The path to a file containing the licence key.",
+						Optional:            true,
+					},
+					"exec": schema.SingleNestedAttribute{
+						MarkdownDescription: "A credential helper command which prints the licence key to standard output. The command must complete within 30 seconds.",
+						Optional:            true,
+						Attributes: map[string]schema.Attribute{
+							"command": schema.StringAttribute{
+								MarkdownDescription: "The command to run.",
+								Required:            true,
+							},
+							"args": schema.ListAttribute{
+								MarkdownDescription: "Arguments to pass to the command.",
+								Optional:            true,
+								ElementType:         types.StringType,
+							},
+							"env": schema.MapAttribute{
+								MarkdownDescription: "Additional environment variables to set for the command.",
+								Optional:            true,
+								ElementType:         types.StringType,
+							},
+						},
+					},
+				},
+			},
+			"config_json": schema.StringAttribute{
+				MarkdownDescription: "The provider configuration as a JSON document, with keys matching the names of the provider's attributes, including `credential_source`. Attributes set directly in the provider block take precedence, and if either `licence_key` or `credential_source` is set there, neither is taken from the JSON document. May also be provided with the `DEPLOYMETA_CONFIG_JSON` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"request_timeout": schema.StringAttribute{
+				MarkdownDescription: "The time allowed for each Common Fate Factory API request, as a Go duration string such as `90s` or `2m`. Defaults to `60s`.",
+				Optional:            true,
 			},
 		},
 	}
@@ -59,9 +113,93 @@ func (p *DeploymentProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
-	cfg, err := factoryconfig.Load(context.Background(), factoryconfig.Opts{
-		LicenceKey: data.LicenceKey.ValueString(),
-		BaseURL:    data.BaseURL.ValueString(),
+	// Values which depend on other resources are unknown until those
+	// resources are applied, and config_json can't fill them in.
+	var unknown []path.Path
+
+	for _, v := range []struct {
+		name  string
+		value attr.Value
+	}{
+		{name: "base_url", value: data.BaseURL},
+		{name: "licence_key", value: data.LicenceKey},
+		{name: "config_json", value: data.ConfigJSON},
+		{name: "request_timeout", value: data.RequestTimeout},
+		{name: "credential_source", value: data.CredentialSource},
+	} {
+		unknown = append(unknown, unknownPaths(path.Root(v.name), v.value)...)
+	}
+
+	for _, p := range unknown {
+		resp.Diagnostics.AddAttributeError(
+			p,
+			"Unknown provider configuration value",
+			fmt.Sprintf("The provider cannot create the Common Fate Factory client as there is an unknown configuration value for %s. Either apply the source of the value first, set the value statically in the configuration, or provide it with config_json or the DEPLOYMETA_CONFIG_JSON environment variable.", p),
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configJSON := data.ConfigJSON.ValueString()
+	if configJSON == "" {
+		configJSON = os.Getenv("DEPLOYMETA_CONFIG_JSON")
+	}
+
+	if configJSON != "" {
+		err := mergeConfigJSON(&data, configJSON)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("config_json"), "Invalid provider configuration JSON", err.Error())
+			return
+		}
+	}
+
+	var credentials deploymeta.CredentialSource
+
+	switch {
+	case data.LicenceKey.ValueString() != "" && !data.CredentialSource.IsNull():
+		resp.Diagnostics.AddAttributeError(path.Root("credential_source"), "Conflicting licence key configuration", "Only one of licence_key and credential_source may be set.")
+		return
+	case data.LicenceKey.ValueString() != "":
+		credentials = deploymeta.StaticCredentials(data.LicenceKey.ValueString())
+	case !data.CredentialSource.IsNull():
+		source, diags := credentialSource(ctx, data.CredentialSource)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		credentials = source
+	default:
+		resp.Diagnostics.AddAttributeError(path.Root("licence_key"), "Missing licence key", "The licence_key attribute must be set in the provider configuration or in config_json, or a credential_source must be configured.")
+		return
+	}
+
+	var requestTimeout time.Duration
+
+	if !data.RequestTimeout.IsNull() {
+		d, err := time.ParseDuration(data.RequestTimeout.ValueString())
+		if err != nil || d <= 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("request_timeout"), "Invalid request timeout", fmt.Sprintf("The request_timeout '%s' must be a positive duration such as '90s' or '2m'.", data.RequestTimeout.ValueString()))
+			return
+		}
+
+		requestTimeout = d
+	}
+
+	client, err := deploymeta.New(ctx, deploymeta.Opts{
+		Credentials:        credentials,
+		BaseURL:            data.BaseURL.ValueString(),
+		RequestTimeout:     requestTimeout,
+		RequestTimeoutHint: "Raise the request_timeout attribute in the deploymeta provider configuration to allow more time",
+		OnThrottle: func(ctx context.Context, procedure string, wait, total time.Duration) {
+			tflog.Debug(ctx, fmt.Sprintf("throttled by the Common Fate Factory for %s total during this run", total), map[string]any{
+				"procedure": procedure,
+				"wait":      wait.String(),
+			})
+		},
 	})
 
 	if err != nil {
@@ -69,8 +207,49 @@ func (p *DeploymentProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
-	resp.DataSourceData = cfg
-	resp.ResourceData = cfg
+	resp.DataSourceData = client
+	resp.ResourceData = client
+}
+
+// unknownPaths returns the paths of the unknown values at or within the
+// value at p.
+func unknownPaths(p path.Path, value attr.Value) []path.Path {
+	if value.IsUnknown() {
+		return []path.Path{p}
+	}
+
+	var paths []path.Path
+
+	switch v := value.(type) {
+	case types.Object:
+		var names []string
+		for name := range v.Attributes() {
+			names = append(names, name)
+		}
+
+		slices.Sort(names)
+
+		for _, name := range names {
+			paths = append(paths, unknownPaths(p.AtName(name), v.Attributes()[name])...)
+		}
+	case types.List:
+		for i, elem := range v.Elements() {
+			paths = append(paths, unknownPaths(p.AtListIndex(i), elem)...)
+		}
+	case types.Map:
+		var keys []string
+		for key := range v.Elements() {
+			keys = append(keys, key)
+		}
+
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			paths = append(paths, unknownPaths(p.AtMapKey(key), v.Elements()[key])...)
+		}
+	}
+
+	return paths
 }
 
 func (p *DeploymentProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -78,17 +257,24 @@ func (p *DeploymentProvider) Resources(ctx context.Context) []func() resource.Re
 		NewDNSRecordResource,
 		NewTerraformOutputResource,
 		NewAWSACMCertificateResource,
+		NewDiagnosticsUploadResource,
+		NewConfigFreezeChecksumResource,
 	}
 }
 
 func (p *DeploymentProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewDeploymentDataSource,
+		NewRegistrationDiffDataSource,
+		NewDeploymentSummaryMarkdownDataSource,
+		NewProviderCapabilitiesDataSource,
 	}
 }
 
 func (p *DeploymentProvider) Functions(ctx context.Context) []func() function.Function {
-	return []func() function.Function{}
+	return []func() function.Function{
+		NewDNSRecordFQDNFunction,
+	}
 }
 
 func New(version string) func() provider.Provider {
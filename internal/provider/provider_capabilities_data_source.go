@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &ProviderCapabilitiesDataSource{}
+
+func NewProviderCapabilitiesDataSource() datasource.DataSource {
+	return &ProviderCapabilitiesDataSource{}
+}
+
+// ProviderCapabilitiesDataSource defines the data source implementation.
+type ProviderCapabilitiesDataSource struct {
+	client *deploymeta.Client
+}
+
+// ProviderCapabilitiesDataSourceModel describes the data source data model.
+type ProviderCapabilitiesDataSourceModel struct {
+	Features types.Map `tfsdk:"features"`
+}
+
+func (d *ProviderCapabilitiesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_provider_capabilities"
+}
+
+func (d *ProviderCapabilitiesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Reports which Factory features are available to the current Common Fate deployment, so that modules can skip resources the Factory or licence doesn't support.",
+
+		Attributes: map[string]schema.Attribute{
+			"features": schema.MapAttribute{
+				MarkdownDescription: "Whether each feature is available, keyed by feature: 'deployment', 'terraform_output', 'dns_records', 'aws_acm_certificates' and 'cloud_support'. Support for 'cloud_support' is inferred from whether the Factory serves its API, so it may be reported as unavailable if a proxy in front of the Factory rejects the check",
+				Computed:            true,
+				ElementType:         types.BoolType,
+			},
+		},
+	}
+}
+
+func (d *ProviderCapabilitiesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*deploymeta.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *deploymeta.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ProviderCapabilitiesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
+	var data ProviderCapabilitiesDataSourceModel
+
+	capabilities, err := d.client.Capabilities(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Common Fate Factory capabilities, got error: %s", err))
+		return
+	}
+
+	features := make(map[string]bool, len(capabilities))
+	for feature, supported := range capabilities {
+		features[string(feature)] = supported
+	}
+
+	featuresValue, diags := types.MapValueFrom(ctx, types.BoolType, features)
+	resp.Diagnostics.Append(diags...)
+	data.Features = featuresValue
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tflog.Trace(ctx, "read factory capabilities")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
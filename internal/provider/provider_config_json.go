@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// providerConfigJSON is the document accepted by the config_json provider
+// attribute and the DEPLOYMETA_CONFIG_JSON environment variable. The keys
+// match the provider attribute names.
+type providerConfigJSON struct {
+	BaseURL        *string `json:"base_url"`
+	LicenceKey     *string `json:"licence_key"`
+	RequestTimeout *string `json:"request_timeout"`
+
+	CredentialSource *credentialSourceJSON `json:"credential_source"`
+}
+
+// credentialSourceJSON is the credential_source object of a JSON provider
+// configuration document.
+type credentialSourceJSON struct {
+	Env  *string `json:"env"`
+	File *string `json:"file"`
+	Exec *struct {
+		Command string            `json:"command"`
+		Args    []string          `json:"args"`
+		Env     map[string]string `json:"env"`
+	} `json:"exec"`
+}
+
+// mergeConfigJSON decodes a JSON provider configuration document and uses it
+// to fill in any attributes which are not set in the provider block.
+// Attributes set in the provider block take precedence. If either licence_key
+// or credential_source is set in the provider block, neither is taken from
+// the document.
+func mergeConfigJSON(data *DeploymentProviderModel, doc string) error {
+	var cfg providerConfigJSON
+
+	dec := json.NewDecoder(bytes.NewBufferString(doc))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&cfg); err != nil {
+		return fmt.Errorf("unable to parse the provider configuration JSON: %w", err)
+	}
+
+	mergeString(&data.BaseURL, cfg.BaseURL)
+	mergeString(&data.RequestTimeout, cfg.RequestTimeout)
+
+	// licence_key and credential_source are taken from the document as a
+	// group, and credential_source as a whole rather than attribute by
+	// attribute, as only one way of obtaining the licence key may be set.
+	if data.LicenceKey.IsNull() && data.CredentialSource.IsNull() {
+		mergeString(&data.LicenceKey, cfg.LicenceKey)
+
+		if cfg.CredentialSource != nil {
+			data.CredentialSource = cfg.CredentialSource.object()
+		}
+	}
+
+	return nil
+}
+
+// object converts the document's credential_source into the value of the
+// credential_source provider attribute.
+func (c *credentialSourceJSON) object() types.Object {
+	exec := types.ObjectNull(execCredentialSourceAttrTypes)
+
+	if c.Exec != nil {
+		args := types.ListNull(types.StringType)
+		if c.Exec.Args != nil {
+			var elems []attr.Value
+			for _, arg := range c.Exec.Args {
+				elems = append(elems, types.StringValue(arg))
+			}
+
+			args = types.ListValueMust(types.StringType, elems)
+		}
+
+		env := types.MapNull(types.StringType)
+		if c.Exec.Env != nil {
+			elems := map[string]attr.Value{}
+			for k, v := range c.Exec.Env {
+				elems[k] = types.StringValue(v)
+			}
+
+			env = types.MapValueMust(types.StringType, elems)
+		}
+
+		exec = types.ObjectValueMust(execCredentialSourceAttrTypes, map[string]attr.Value{
+			"command": types.StringValue(c.Exec.Command),
+			"args":    args,
+			"env":     env,
+		})
+	}
+
+	return types.ObjectValueMust(credentialSourceAttrTypes, map[string]attr.Value{
+		"env":  types.StringPointerValue(c.Env),
+		"file": types.StringPointerValue(c.File),
+		"exec": exec,
+	})
+}
+
+func mergeString(dst *types.String, src *string) {
+	if dst.IsNull() && src != nil {
+		*dst = types.StringValue(*src)
+	}
+}
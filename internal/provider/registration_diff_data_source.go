@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"connectrpc.com/connect"
+	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
+	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RegistrationDiffDataSource{}
+
+func NewRegistrationDiffDataSource() datasource.DataSource {
+	return &RegistrationDiffDataSource{}
+}
+
+// RegistrationDiffDataSource defines the data source implementation.
+type RegistrationDiffDataSource struct {
+	client *deploymeta.Client
+}
+
+// RegistrationDiffDataSourceModel describes the data source data model.
+type RegistrationDiffDataSourceModel struct {
+	OtherLicenceKey types.String                  `tfsdk:"other_licence_key"`
+	IgnoreFields    types.Set                     `tfsdk:"ignore_fields"`
+	Identical       types.Bool                    `tfsdk:"identical"`
+	Differences     []RegistrationDifferenceModel `tfsdk:"differences"`
+}
+
+// RegistrationDifferenceModel describes a registered field which differs between deployments.
+type RegistrationDifferenceModel struct {
+	Field      types.String `tfsdk:"field"`
+	Value      types.String `tfsdk:"value"`
+	OtherValue types.String `tfsdk:"other_value"`
+}
+
+func (d *RegistrationDiffDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_registration_diff"
+}
+
+func (d *RegistrationDiffDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Compares the registrations of the current Common Fate deployment with another deployment, such as staging and production. Every registered field identifies the deployment's own infrastructure, so fields are compared by whether they are registered rather than by value.",
+
+		Attributes: map[string]schema.Attribute{
+			"other_licence_key": schema.StringAttribute{
+				MarkdownDescription: "The licence key of the deployment to compare against",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"ignore_fields": schema.SetAttribute{
+				MarkdownDescription: "Fields to leave out of the comparison, for example 'terraform_output.vpc_id'",
+				Optional:            true,
+				ElementType:         types.StringType,
+			},
+			"identical": schema.BoolAttribute{
+				MarkdownDescription: "Whether the same fields are registered for both deployments",
+				Computed:            true,
+			},
+			"differences": schema.ListNestedAttribute{
+				MarkdownDescription: "The fields which are registered for only one of the deployments, sorted by field",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"field": schema.StringAttribute{
+							MarkdownDescription: "The registered field, for example 'terraform_output.saml_sso_acs_url'",
+							Computed:            true,
+						},
+						"value": schema.StringAttribute{
+							MarkdownDescription: "The value registered for the current deployment",
+							Computed:            true,
+						},
+						"other_value": schema.StringAttribute{
+							MarkdownDescription: "The value registered for the other deployment",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *RegistrationDiffDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*deploymeta.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *deploymeta.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *RegistrationDiffDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, reportThrottling := trackThrottling(ctx)
+	defer reportThrottling(&resp.Diagnostics)
+
+	var data RegistrationDiffDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var ignoreFields []string
+
+	resp.Diagnostics.Append(data.IgnoreFields.ElementsAs(ctx, &ignoreFields, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	other, err := d.client.WithLicenceKey(ctx, data.OtherLicenceKey.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error loading Common Fate deployment configuration", err.Error())
+		return
+	}
+
+	fields, err := registeredFields(ctx, d.client.Deployment())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read Common Fate deployment registrations, got error: %s", err))
+		return
+	}
+
+	otherFields, err := registeredFields(ctx, other.Deployment())
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read the other Common Fate deployment's registrations, got error: %s", err))
+		return
+	}
+
+	for _, field := range ignoreFields {
+		delete(fields, field)
+		delete(otherFields, field)
+	}
+
+	var names []string
+	for field := range fields {
+		names = append(names, field)
+	}
+
+	sort.Strings(names)
+
+	data.Differences = []RegistrationDifferenceModel{}
+
+	for _, field := range names {
+		// Registered values are unique to each deployment, so only
+		// whether the field is registered is compared.
+		if (fields[field] != "") == (otherFields[field] != "") {
+			continue
+		}
+
+		data.Differences = append(data.Differences, RegistrationDifferenceModel{
+			Field:      types.StringValue(field),
+			Value:      types.StringValue(fields[field]),
+			OtherValue: types.StringValue(otherFields[field]),
+		})
+	}
+
+	data.Identical = types.BoolValue(len(data.Differences) == 0)
+
+	tflog.Trace(ctx, "compared deployment registrations")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// registeredFields returns the registered fields of a deployment, keyed by
+// field name. Fields which haven't been registered, including every Terraform
+// output field if the outputs haven't been registered, are empty.
+func registeredFields(ctx context.Context, client deploymentv1alpha1connect.DeploymentServiceClient) (map[string]string, error) {
+	deploymentRes, err := client.GetDeployment(ctx, connect.NewRequest(&deploymentv1alpha1.GetDeploymentRequest{}))
+	if err != nil {
+		return nil, err
+	}
+
+	// output is nil if the outputs haven't been registered, and its getters
+	// then return empty values.
+	var output *deploymentv1alpha1.TerraformOutput
+
+	outputRes, err := client.GetTerraformOutput(ctx, connect.NewRequest(&deploymentv1alpha1.GetTerraformOutputRequest{}))
+	if err != nil && connect.CodeOf(err) != connect.CodeNotFound {
+		return nil, err
+	}
+
+	if err == nil {
+		output = outputRes.Msg.GetOutput()
+	}
+
+	return map[string]string{
+		"deployment.dns_zone_name":                          deploymentRes.Msg.GetDeployment().GetDnsZoneName(),
+		"terraform_output.saml_sso_acs_url":                 output.GetSamlSsoAcsUrl(),
+		"terraform_output.saml_sso_entity_id":               output.GetSamlSsoEntityId(),
+		"terraform_output.cognito_user_pool_id":             output.GetCognitoUserPoolId(),
+		"terraform_output.dns_cname_record_for_app_domain":  output.GetDnsCnameRecordForAppDomain(),
+		"terraform_output.dns_cname_record_for_auth_domain": output.GetDnsCnameRecordForAuthDomain(),
+		"terraform_output.web_client_id":                    output.GetWebClientId(),
+		"terraform_output.cli_client_id":                    output.GetCliClientId(),
+		"terraform_output.terraform_client_id":              output.GetTerraformClientId(),
+		"terraform_output.read_only_client_id":              output.GetReadOnlyClientId(),
+		"terraform_output.provisioner_client_id":            output.GetProvisionerClientId(),
+		"terraform_output.vpc_id":                           output.GetVpcId(),
+	}, nil
+}
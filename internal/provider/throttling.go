@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/common-fate/terraform-provider-deploymeta/pkg/deploymeta"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// trackThrottling returns a context which records Factory throttling of the
+// requests made with it, and a function which adds a single warning to the
+// diagnostics of the operation if any of those requests were throttled.
+func trackThrottling(ctx context.Context) (context.Context, func(diags *diag.Diagnostics)) {
+	ctx, stats := deploymeta.WithThrottleStats(ctx)
+
+	return ctx, func(diags *diag.Diagnostics) {
+		if stats.Count() == 0 {
+			return
+		}
+
+		diags.AddWarning(
+			"Throttled by the Common Fate Factory",
+			fmt.Sprintf("The Common Fate Factory throttled requests made by this operation %d time(s), which waited %s in total before retrying. Reduce Terraform's parallelism if this happens often.", stats.Count(), stats.Total()),
+		)
+	}
+}
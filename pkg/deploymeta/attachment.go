@@ -0,0 +1,76 @@
+package deploymeta
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	supportv1alpha1 "github.com/common-fate/sdk/gen/commonfate/control/support/v1alpha1"
+)
+
+// UploadAttachment uploads the contents of an attachment created with the
+// CloudSupport service's CreateAttachment RPC to its presigned upload URL.
+// The upload is bounded by the client's request timeout.
+func (c *Client) UploadAttachment(ctx context.Context, attachment *supportv1alpha1.Attachment, content []byte) error {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+
+	for _, field := range attachment.FormData {
+		if err := w.WriteField(field.Key, field.Value); err != nil {
+			return err
+		}
+	}
+
+	part, err := w.CreateFormFile("file", attachment.FileName)
+	if err != nil {
+		return err
+	}
+
+	if _, err := part.Write(content); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	uploadCtx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(uploadCtx, http.MethodPost, attachment.Url, &body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	// the presigned URL is not a Factory endpoint, so the default client is
+	// used rather than the Factory client, which sends the licence key.
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// only describe the timeout if our deadline fired, rather than
+		// a deadline or cancellation coming from the caller.
+		if ctx.Err() == nil && errors.Is(uploadCtx.Err(), context.DeadlineExceeded) {
+			msg := fmt.Sprintf("uploading attachment %s did not complete within the %s request timeout", attachment.FileName, c.requestTimeout)
+			if c.requestTimeoutHint != "" {
+				msg += ". " + c.requestTimeoutHint
+			}
+
+			return errors.New(msg)
+		}
+
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("attachment upload failed with status %d: %s", res.StatusCode, string(msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package deploymeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"connectrpc.com/connect"
+	"github.com/common-fate/sdk/gen/commonfate/factory/cloudsupport/v1alpha1/cloudsupportv1alpha1connect"
+	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
+)
+
+// Feature is a group of Factory RPCs which a deployment may or may not
+// have access to, depending on the Factory build and licence tier.
+type Feature string
+
+const (
+	FeatureDeployment         Feature = "deployment"
+	FeatureTerraformOutput    Feature = "terraform_output"
+	FeatureDNSRecords         Feature = "dns_records"
+	FeatureAWSACMCertificates Feature = "aws_acm_certificates"
+	FeatureCloudSupport       Feature = "cloud_support"
+)
+
+// Features lists every feature reported by Capabilities.
+var Features = []Feature{
+	FeatureDeployment,
+	FeatureTerraformOutput,
+	FeatureDNSRecords,
+	FeatureAWSACMCertificates,
+	FeatureCloudSupport,
+}
+
+// Inferred returns true if support for the feature is inferred from whether
+// the Factory serves its routes, rather than confirmed by calling one of its
+// RPCs. A proxy in front of the Factory which rejects the probe makes an
+// inferred feature appear unsupported, so callers shouldn't rely on an
+// unsupported result for an inferred feature.
+func (f Feature) Inferred() bool {
+	return f == FeatureCloudSupport
+}
+
+// capabilityCache holds the result of each successful probe, so that each
+// feature is probed at most once per client.
+type capabilityCache struct {
+	mu        sync.Mutex
+	supported map[Feature]bool
+}
+
+// Capabilities probes the Factory with a read-only request for each feature
+// and reports whether the feature is available to the deployment. Features
+// without a read-only RPC are probed by sending a GET request to one of their
+// procedures, which the Factory rejects without running the RPC. A feature
+// is unavailable if the Factory doesn't implement its RPCs or the licence
+// doesn't permit them. Any other response, including NotFound for the
+// nonexistent IDs used by the probes, means the feature is available.
+//
+// Results are cached for the lifetime of the client. Failed probes are
+// not cached.
+func (c *Client) Capabilities(ctx context.Context) (map[Feature]bool, error) {
+	capabilities := make(map[Feature]bool, len(Features))
+
+	for _, feature := range Features {
+		supported, err := c.Supports(ctx, feature)
+		if err != nil {
+			return nil, err
+		}
+
+		capabilities[feature] = supported
+	}
+
+	return capabilities, nil
+}
+
+// Supports reports whether the feature is available to the deployment,
+// probing the Factory for that feature alone if it hasn't been probed yet.
+func (c *Client) Supports(ctx context.Context, feature Feature) (bool, error) {
+	c.capabilities.mu.Lock()
+	defer c.capabilities.mu.Unlock()
+
+	if supported, ok := c.capabilities.supported[feature]; ok {
+		return supported, nil
+	}
+
+	if !slices.Contains(Features, feature) {
+		return false, fmt.Errorf("unknown Factory feature %q", feature)
+	}
+
+	supported, err := probeResult(c.probe(ctx, feature))
+	if err != nil {
+		return false, fmt.Errorf("probing the Factory for %s support: %w", feature, err)
+	}
+
+	if c.capabilities.supported == nil {
+		c.capabilities.supported = map[Feature]bool{}
+	}
+
+	c.capabilities.supported[feature] = supported
+
+	return supported, nil
+}
+
+// probe sends the read-only request used to detect the feature.
+func (c *Client) probe(ctx context.Context, feature Feature) error {
+	deployments := c.Deployment()
+
+	var err error
+
+	switch feature {
+	case FeatureDeployment:
+		_, err = deployments.GetDeployment(ctx, connect.NewRequest(&deploymentv1alpha1.GetDeploymentRequest{}))
+	case FeatureTerraformOutput:
+		_, err = deployments.GetTerraformOutput(ctx, connect.NewRequest(&deploymentv1alpha1.GetTerraformOutputRequest{}))
+	case FeatureDNSRecords:
+		_, err = deployments.GetDNSRecord(ctx, connect.NewRequest(&deploymentv1alpha1.GetDNSRecordRequest{}))
+	case FeatureAWSACMCertificates:
+		_, err = deployments.GetAWSACMCertificate(ctx, connect.NewRequest(&deploymentv1alpha1.GetAWSACMCertificateRequest{}))
+	case FeatureCloudSupport:
+		err = c.probeRoute(ctx, cloudsupportv1alpha1connect.CloudSupportServiceCreateAttachmentProcedure)
+	}
+
+	return err
+}
+
+// probeRoute checks whether the Factory serves a procedure which has side
+// effects, without calling it. Connect servers reject GET requests for such
+// procedures with 405 Method Not Allowed before running the RPC, whereas
+// procedures the server doesn't have are not found.
+func (c *Client) probeRoute(ctx context.Context, procedure string) error {
+	ctx, cancel := context.WithTimeout(ctx, c.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.cfg.BaseURL, "/")+procedure, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return connect.NewError(connect.CodeUnavailable, err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+	}()
+
+	switch res.StatusCode {
+	case http.StatusNotFound:
+		return connect.NewError(connect.CodeUnimplemented, fmt.Errorf("%s was not found", procedure))
+	case http.StatusUnauthorized:
+		return connect.NewError(connect.CodeUnauthenticated, errors.New(res.Status))
+	case http.StatusForbidden:
+		return connect.NewError(connect.CodePermissionDenied, errors.New(res.Status))
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return connect.NewError(connect.CodeUnavailable, errors.New(res.Status))
+	}
+
+	return nil
+}
+
+// probeResult converts the error returned by a probe into whether the
+// probed feature is supported. Errors which say nothing about the feature,
+// such as an invalid licence key or an unreachable Factory, are returned.
+func probeResult(err error) (bool, error) {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnimplemented, connect.CodePermissionDenied:
+		return false, nil
+	case connect.CodeUnauthenticated, connect.CodeUnavailable, connect.CodeDeadlineExceeded, connect.CodeCanceled:
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,69 @@
+package deploymeta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/common-fate/sdk/factoryconfig"
+	cloudsupportv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/cloudsupport/v1alpha1"
+	"github.com/common-fate/sdk/gen/commonfate/factory/cloudsupport/v1alpha1/cloudsupportv1alpha1connect"
+)
+
+// recordingCloudSupportService counts the attachments it is asked to create.
+type recordingCloudSupportService struct {
+	cloudsupportv1alpha1connect.UnimplementedCloudSupportServiceHandler
+
+	created atomic.Int32
+}
+
+func (s *recordingCloudSupportService) CreateAttachment(ctx context.Context, req *connect.Request[cloudsupportv1alpha1.CreateAttachmentRequest]) (*connect.Response[cloudsupportv1alpha1.CreateAttachmentResponse], error) {
+	s.created.Add(1)
+	return connect.NewResponse(&cloudsupportv1alpha1.CreateAttachmentResponse{}), nil
+}
+
+func TestSupportsCloudSupport(t *testing.T) {
+	t.Run("served", func(t *testing.T) {
+		svc := &recordingCloudSupportService{}
+
+		mux := http.NewServeMux()
+		mux.Handle(cloudsupportv1alpha1connect.NewCloudSupportServiceHandler(svc))
+
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		client := NewFromConfig(&factoryconfig.Context{BaseURL: server.URL, HTTPClient: server.Client()})
+
+		supported, err := client.Supports(context.Background(), FeatureCloudSupport)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !supported {
+			t.Error("expected cloud support to be supported")
+		}
+
+		if got := svc.created.Load(); got != 0 {
+			t.Errorf("expected the probe not to create an attachment, got %d", got)
+		}
+	})
+
+	t.Run("not served", func(t *testing.T) {
+		server := httptest.NewServer(http.NewServeMux())
+		defer server.Close()
+
+		client := NewFromConfig(&factoryconfig.Context{BaseURL: server.URL, HTTPClient: server.Client()})
+
+		supported, err := client.Supports(context.Background(), FeatureCloudSupport)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if supported {
+			t.Error("expected cloud support to be unsupported")
+		}
+	})
+}
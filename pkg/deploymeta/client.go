@@ -0,0 +1,164 @@
+// Package deploymeta is a Go client for the Common Fate Factory services
+// used by the deploymeta Terraform provider. It wraps client creation,
+// retries and conversions so that other Go tooling can talk to the Factory
+// exactly the same way the provider does.
+package deploymeta
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/common-fate/sdk/factory/service/cloudsupport"
+	"github.com/common-fate/sdk/factory/service/deployment"
+	"github.com/common-fate/sdk/factory/service/monitoring"
+	"github.com/common-fate/sdk/factoryconfig"
+	"github.com/common-fate/sdk/gen/commonfate/factory/cloudsupport/v1alpha1/cloudsupportv1alpha1connect"
+	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+)
+
+type Opts struct {
+	LicenceKey string
+
+	// Credentials provides the licence key if LicenceKey is not set.
+	Credentials CredentialSource
+
+	// BaseURL of the Factory service to connect to.
+	// Defaults to "https://factory.commonfate.io"
+	// if not provided.
+	BaseURL string
+
+	// MaxRetries is the number of times a read-only request which failed
+	// with a retryable error is retried. Requests which create or change
+	// objects are only retried if the Factory throttled them with a
+	// Retry-After delay. Defaults to 3 if not provided.
+	// Set to a negative value to disable retries.
+	MaxRetries int
+
+	// OnThrottle is called whenever a throttled request is retried after
+	// the delay requested by the Factory.
+	OnThrottle ThrottleFunc
+
+	// RequestTimeout is the time allowed for each request attempt.
+	// Defaults to DefaultRequestTimeout if not provided.
+	RequestTimeout time.Duration
+
+	// RequestTimeoutHint is appended to the error returned when a request
+	// exceeds RequestTimeout, to tell the user how to raise the timeout.
+	RequestTimeoutHint string
+}
+
+// Client holds the Factory configuration and the connect options
+// shared by every service client it constructs.
+type Client struct {
+	cfg          *factoryconfig.Context
+	opts         []connect.ClientOption
+	capabilities *capabilityCache
+
+	// requestTimeout and requestTimeoutHint apply to requests which are
+	// not made through a connect client, such as attachment uploads.
+	requestTimeout     time.Duration
+	requestTimeoutHint string
+}
+
+// New loads the Factory configuration and initializes a client.
+func New(ctx context.Context, opts Opts) (*Client, error) {
+	licenceKey := opts.LicenceKey
+
+	if licenceKey == "" && opts.Credentials != nil {
+		key, err := opts.Credentials.LicenceKey(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		licenceKey = key
+	}
+
+	cfg, err := factoryconfig.Load(ctx, factoryconfig.Opts{
+		LicenceKey: licenceKey,
+		BaseURL:    opts.BaseURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	maxRetries := opts.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	requestTimeout := opts.RequestTimeout
+	if requestTimeout == 0 {
+		requestTimeout = DefaultRequestTimeout
+	}
+
+	// the unsupported interceptor is registered first so that it sees the
+	// final error returned for a request.
+	clientOpts := []connect.ClientOption{
+		connect.WithInterceptors(&unsupportedInterceptor{baseURL: cfg.BaseURL}),
+	}
+
+	if maxRetries > 0 {
+		clientOpts = append(clientOpts, connect.WithInterceptors(&retryInterceptor{
+			maxRetries: maxRetries,
+			onThrottle: opts.OnThrottle,
+		}))
+	}
+
+	// the timeout interceptor is registered after the retry interceptor
+	// so that the timeout applies to each attempt.
+	clientOpts = append(clientOpts, connect.WithInterceptors(&timeoutInterceptor{
+		timeout: requestTimeout,
+		hint:    opts.RequestTimeoutHint,
+	}))
+
+	client := NewFromConfig(cfg, clientOpts...)
+	client.requestTimeout = requestTimeout
+	client.requestTimeoutHint = opts.RequestTimeoutHint
+
+	return client, nil
+}
+
+// NewFromConfig initializes a client from an existing Factory configuration.
+// The provided options are applied to every service client.
+func NewFromConfig(cfg *factoryconfig.Context, opts ...connect.ClientOption) *Client {
+	return &Client{cfg: cfg, opts: opts, capabilities: &capabilityCache{}, requestTimeout: DefaultRequestTimeout}
+}
+
+// Config returns the underlying Factory configuration.
+func (c *Client) Config() *factoryconfig.Context {
+	return c.cfg
+}
+
+// Deployment returns a client for the Factory Deployment service.
+func (c *Client) Deployment() deploymentv1alpha1connect.DeploymentServiceClient {
+	return deployment.NewFromConfig(c.cfg, c.opts...)
+}
+
+// Monitoring returns a client for the Factory Monitoring services.
+func (c *Client) Monitoring() *monitoring.Client {
+	return monitoring.NewFromConfig(c.cfg, c.opts...)
+}
+
+// CloudSupport returns a client for the Factory CloudSupport service.
+func (c *Client) CloudSupport() cloudsupportv1alpha1connect.CloudSupportServiceClient {
+	return cloudsupport.NewFromConfig(c.cfg, c.opts...)
+}
+
+// WithLicenceKey returns a client for the deployment the provided licence
+// key belongs to, using the same Factory and client options as this client.
+func (c *Client) WithLicenceKey(ctx context.Context, licenceKey string) (*Client, error) {
+	cfg, err := factoryconfig.Load(ctx, factoryconfig.Opts{
+		LicenceKey: licenceKey,
+		BaseURL:    c.cfg.BaseURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	client := NewFromConfig(cfg, c.opts...)
+	client.requestTimeout = c.requestTimeout
+	client.requestTimeoutHint = c.requestTimeoutHint
+
+	return client, nil
+}
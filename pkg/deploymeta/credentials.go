@@ -0,0 +1,118 @@
+package deploymeta
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultExecTimeout is the time allowed for a credential helper to print
+// the licence key if ExecCredentials.Timeout is not provided.
+const DefaultExecTimeout = 30 * time.Second
+
+// CredentialSource provides the licence key used to authenticate with the Factory.
+type CredentialSource interface {
+	LicenceKey(ctx context.Context) (string, error)
+}
+
+// StaticCredentials is a licence key provided directly.
+type StaticCredentials string
+
+func (c StaticCredentials) LicenceKey(ctx context.Context) (string, error) {
+	if c == "" {
+		return "", errors.New("the licence key is empty")
+	}
+
+	return string(c), nil
+}
+
+// EnvCredentials reads the licence key from the named environment variable.
+type EnvCredentials string
+
+func (c EnvCredentials) LicenceKey(ctx context.Context) (string, error) {
+	key := os.Getenv(string(c))
+	if key == "" {
+		return "", fmt.Errorf("the environment variable %s is not set", string(c))
+	}
+
+	return key, nil
+}
+
+// FileCredentials reads the licence key from the file at the given path.
+// Leading and trailing whitespace is removed.
+type FileCredentials string
+
+func (c FileCredentials) LicenceKey(ctx context.Context) (string, error) {
+	b, err := os.ReadFile(string(c))
+	if err != nil {
+		return "", fmt.Errorf("reading licence key file: %w", err)
+	}
+
+	key := strings.TrimSpace(string(b))
+	if key == "" {
+		return "", fmt.Errorf("the licence key file %s is empty", string(c))
+	}
+
+	return key, nil
+}
+
+// ExecCredentials runs a credential helper command and uses its standard
+// output as the licence key, similar to kubeconfig exec plugins.
+// Leading and trailing whitespace is removed.
+type ExecCredentials struct {
+	Command string
+	Args    []string
+
+	// Env holds additional environment variables for the command,
+	// which also inherits the environment of the current process.
+	Env map[string]string
+
+	// Timeout is the time allowed for the command to complete.
+	// Defaults to DefaultExecTimeout if not provided.
+	Timeout time.Duration
+}
+
+func (c ExecCredentials) LicenceKey(ctx context.Context) (string, error) {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = DefaultExecTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.Command, c.Args...)
+
+	// Killing the command doesn't kill any processes it started, which may
+	// hold its output open. Stop waiting for them shortly after the timeout.
+	cmd.WaitDelay = time.Second
+
+	cmd.Env = os.Environ()
+	for k, v := range c.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", fmt.Errorf("the credential helper %s did not complete within %s", c.Command, timeout)
+		}
+
+		return "", fmt.Errorf("running credential helper %s: %w: %s", c.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return "", fmt.Errorf("the credential helper %s did not return a licence key", c.Command)
+	}
+
+	return key, nil
+}
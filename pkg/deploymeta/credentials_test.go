@@ -0,0 +1,120 @@
+package deploymeta
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("DEPLOYMETA_TEST_LICENCE_KEY", "key")
+
+	got, err := EnvCredentials("DEPLOYMETA_TEST_LICENCE_KEY").LicenceKey(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != "key" {
+		t.Errorf("LicenceKey() = %q, want %q", got, "key")
+	}
+
+	t.Setenv("DEPLOYMETA_TEST_LICENCE_KEY", "")
+
+	_, err = EnvCredentials("DEPLOYMETA_TEST_LICENCE_KEY").LicenceKey(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "DEPLOYMETA_TEST_LICENCE_KEY is not set") {
+		t.Errorf("expected an error naming the unset variable, got %v", err)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	t.Run("trims whitespace", func(t *testing.T) {
+		got, err := FileCredentials(write("key", "  key\n")).LicenceKey(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != "key" {
+			t.Errorf("LicenceKey() = %q, want %q", got, "key")
+		}
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		_, err := FileCredentials(write("empty", "\n")).LicenceKey(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "is empty") {
+			t.Errorf("expected an empty file error, got %v", err)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := FileCredentials(filepath.Join(dir, "missing")).LicenceKey(context.Background())
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("expected a not exist error, got %v", err)
+		}
+	})
+}
+
+func TestExecCredentials(t *testing.T) {
+	t.Run("trims output", func(t *testing.T) {
+		helper := ExecCredentials{Command: "sh", Args: []string{"-c", `printf '  %s\n' "$KEY"`}, Env: map[string]string{"KEY": "key"}}
+
+		got, err := helper.LicenceKey(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got != "key" {
+			t.Errorf("LicenceKey() = %q, want %q", got, "key")
+		}
+	})
+
+	t.Run("empty output", func(t *testing.T) {
+		helper := ExecCredentials{Command: "sh", Args: []string{"-c", "echo"}}
+
+		_, err := helper.LicenceKey(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "did not return a licence key") {
+			t.Errorf("expected a missing licence key error, got %v", err)
+		}
+	})
+
+	t.Run("includes stderr", func(t *testing.T) {
+		helper := ExecCredentials{Command: "sh", Args: []string{"-c", "echo 'not logged in' >&2; exit 1"}}
+
+		_, err := helper.LicenceKey(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "not logged in") {
+			t.Errorf("expected the error to include stderr, got %v", err)
+		}
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		// The sleep runs in a child of the shell, so it keeps the output
+		// open after the shell itself is killed.
+		helper := ExecCredentials{Command: "sh", Args: []string{"-c", "sleep 10; echo key"}, Timeout: 100 * time.Millisecond}
+
+		start := time.Now()
+
+		_, err := helper.LicenceKey(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "did not complete within 100ms") {
+			t.Errorf("expected a timeout error, got %v", err)
+		}
+
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("expected the helper to be abandoned shortly after the timeout, took %s", elapsed)
+		}
+	})
+}
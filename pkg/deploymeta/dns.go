@@ -0,0 +1,52 @@
+package deploymeta
+
+import (
+	"fmt"
+	"strings"
+
+	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
+)
+
+// ParseDNSRecordType converts a DNS record type such as 'TXT' into its API representation.
+func ParseDNSRecordType(s string) (deploymentv1alpha1.DNSRecordType, error) {
+	switch s {
+	case "TXT":
+		return deploymentv1alpha1.DNSRecordType_DNS_RECORD_TYPE_TXT, nil
+	case "CNAME":
+		return deploymentv1alpha1.DNSRecordType_DNS_RECORD_TYPE_CNAME, nil
+	default:
+		return deploymentv1alpha1.DNSRecordType_DNS_RECORD_TYPE_UNSPECIFIED, fmt.Errorf("the DNS record type '%s' is invalid. Valid values are ['TXT', 'CNAME']", s)
+	}
+}
+
+// DNSRecordTypeString converts an API DNS record type into its string form, such as 'TXT'.
+func DNSRecordTypeString(t deploymentv1alpha1.DNSRecordType) string {
+	switch t {
+	case deploymentv1alpha1.DNSRecordType_DNS_RECORD_TYPE_TXT:
+		return "TXT"
+	case deploymentv1alpha1.DNSRecordType_DNS_RECORD_TYPE_CNAME:
+		return "CNAME"
+	default:
+		return ""
+	}
+}
+
+// DNSRecordFQDN joins a DNS record name and zone name into the canonical
+// fully-qualified domain name: lowercase and without a trailing dot.
+// An empty name or '@' refers to the zone apex, and names which already
+// end with the zone name are not qualified again.
+func DNSRecordFQDN(name, zoneName string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	zoneName = strings.ToLower(strings.TrimSuffix(zoneName, "."))
+
+	switch {
+	case name == "" || name == "@":
+		return zoneName
+	case zoneName == "":
+		return name
+	case name == zoneName || strings.HasSuffix(name, "."+zoneName):
+		return name
+	default:
+		return name + "." + zoneName
+	}
+}
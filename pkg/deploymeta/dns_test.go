@@ -0,0 +1,32 @@
+package deploymeta
+
+import "testing"
+
+func TestDNSRecordFQDN(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   string
+		zoneName string
+		want     string
+	}{
+		{name: "relative name", record: "www", zoneName: "example.com", want: "www.example.com"},
+		{name: "empty name is the apex", record: "", zoneName: "example.com", want: "example.com"},
+		{name: "at sign is the apex", record: "@", zoneName: "example.com", want: "example.com"},
+		{name: "trailing dots", record: "www.", zoneName: "example.com.", want: "www.example.com"},
+		{name: "already qualified", record: "www.example.com", zoneName: "example.com", want: "www.example.com"},
+		{name: "already qualified with trailing dot", record: "www.example.com.", zoneName: "example.com", want: "www.example.com"},
+		{name: "name equal to zone", record: "example.com", zoneName: "example.com", want: "example.com"},
+		{name: "similar suffix is not the zone", record: "wwwexample.com", zoneName: "example.com", want: "wwwexample.com.example.com"},
+		{name: "mixed case", record: "WWW", zoneName: "Example.COM", want: "www.example.com"},
+		{name: "empty zone", record: "www.example.com", zoneName: "", want: "www.example.com"},
+		{name: "validation record", record: "_acme-challenge.auth", zoneName: "abc123.commonfate.app", want: "_acme-challenge.auth.abc123.commonfate.app"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DNSRecordFQDN(tt.record, tt.zoneName); got != tt.want {
+				t.Errorf("DNSRecordFQDN(%q, %q) = %q, want %q", tt.record, tt.zoneName, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package deploymeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+)
+
+const (
+	defaultMaxRetries = 3
+	baseRetryDelay    = 500 * time.Millisecond
+	maxRetryDelay     = 10 * time.Second
+
+	// maxRetryAfter is the longest Retry-After delay which will be waited
+	// for. Requests asked to wait longer than this fail immediately.
+	maxRetryAfter = time.Minute
+)
+
+// retryableProcedures are the read-only procedures which are retried after
+// any transient error. Other procedures are only retried when they are
+// throttled: a request which failed with Unavailable may already have been
+// received and applied by the Factory, and retrying it could create
+// duplicate objects, whereas a throttled request is rejected before it is
+// processed.
+var retryableProcedures = map[string]bool{
+	deploymentv1alpha1connect.DeploymentServiceGetDeploymentProcedure:        true,
+	deploymentv1alpha1connect.DeploymentServiceGetDNSRecordProcedure:         true,
+	deploymentv1alpha1connect.DeploymentServiceGetAWSACMCertificateProcedure: true,
+	deploymentv1alpha1connect.DeploymentServiceGetTerraformOutputProcedure:   true,
+}
+
+// ThrottleFunc is called when the Factory throttles a request and the
+// request will be retried after wait. total is the time spent waiting
+// on throttled requests by the client so far.
+type ThrottleFunc func(ctx context.Context, procedure string, wait, total time.Duration)
+
+// ThrottleStats accumulates the requests throttled by the Factory while
+// serving calls made with a context returned by WithThrottleStats.
+type ThrottleStats struct {
+	count atomic.Int64
+	total atomic.Int64
+}
+
+// Count returns the number of times requests were throttled.
+func (s *ThrottleStats) Count() int {
+	return int(s.count.Load())
+}
+
+// Total returns the total time spent waiting on Retry-After delays.
+func (s *ThrottleStats) Total() time.Duration {
+	return time.Duration(s.total.Load())
+}
+
+type throttleStatsContextKey struct{}
+
+// WithThrottleStats returns a context which records throttling of requests
+// made with it, so that callers can report throttling once per operation.
+func WithThrottleStats(ctx context.Context) (context.Context, *ThrottleStats) {
+	stats := &ThrottleStats{}
+	return context.WithValue(ctx, throttleStatsContextKey{}, stats), stats
+}
+
+// retryInterceptor retries read-only unary calls which failed with a transient
+// error, and any unary call which the Factory throttled with a Retry-After
+// delay. If the Factory provides a Retry-After delay, it is waited for before
+// retrying; otherwise the interceptor backs off exponentially between attempts.
+type retryInterceptor struct {
+	maxRetries int
+	onThrottle ThrottleFunc
+
+	// throttled is the total time in nanoseconds spent waiting on Retry-After delays.
+	throttled atomic.Int64
+}
+
+func (i *retryInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		readOnly := retryableProcedures[req.Spec().Procedure]
+
+		delay := baseRetryDelay
+
+		for attempt := 0; ; attempt++ {
+			res, err := next(ctx, req)
+			if err == nil || attempt >= i.maxRetries || !isRetryable(err) {
+				return res, err
+			}
+
+			wait := delay
+
+			// Mutating requests are only retried if the Factory rejected
+			// them before processing them and said when to try again.
+			retryAfter, ok := retryAfterDelay(err)
+			if !readOnly && !(ok && connect.CodeOf(err) == connect.CodeResourceExhausted) {
+				return res, err
+			}
+
+			if ok {
+				if retryAfter > maxRetryAfter {
+					return res, err
+				}
+
+				wait = retryAfter
+				total := time.Duration(i.throttled.Add(int64(wait)))
+
+				if stats, ok := ctx.Value(throttleStatsContextKey{}).(*ThrottleStats); ok {
+					stats.count.Add(1)
+					stats.total.Add(int64(wait))
+				}
+
+				if i.onThrottle != nil {
+					i.onThrottle(ctx, req.Spec().Procedure, wait, total)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil, err
+			case <-time.After(wait):
+			}
+
+			delay *= 2
+			if delay > maxRetryDelay {
+				delay = maxRetryDelay
+			}
+		}
+	}
+}
+
+func (i *retryInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *retryInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
+
+// isRetryable returns true if the error indicates the Factory was
+// temporarily unable to serve the request.
+func isRetryable(err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfterDelay returns the delay requested by the Retry-After header
+// of an error response, given either in seconds or as an HTTP date.
+func retryAfterDelay(err error) (time.Duration, bool) {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		return 0, false
+	}
+
+	value := connectErr.Meta().Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+
+		return d, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,189 @@
+package deploymeta
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
+	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+)
+
+func TestRetryAfterDelay(t *testing.T) {
+	withRetryAfter := func(value string) error {
+		err := connect.NewError(connect.CodeResourceExhausted, errors.New("slow down"))
+		if value != "" {
+			err.Meta().Set("Retry-After", value)
+		}
+
+		return err
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "seconds", err: withRetryAfter("5"), want: 5 * time.Second, wantOK: true},
+		{name: "zero seconds", err: withRetryAfter("0"), want: 0, wantOK: true},
+		{name: "negative seconds", err: withRetryAfter("-1"), wantOK: false},
+		{name: "past HTTP date", err: withRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)), want: 0, wantOK: true},
+		{name: "invalid value", err: withRetryAfter("soon"), wantOK: false},
+		{name: "missing header", err: withRetryAfter(""), wantOK: false},
+		{name: "not a connect error", err: errors.New("boom"), wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := retryAfterDelay(tt.err)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("retryAfterDelay() = %s, %v, want %s, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	t.Run("future HTTP date", func(t *testing.T) {
+		got, ok := retryAfterDelay(withRetryAfter(time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)))
+		if !ok || got <= 25*time.Second || got > 30*time.Second {
+			t.Errorf("retryAfterDelay() = %s, %v, want about 30s, true", got, ok)
+		}
+	})
+}
+
+// throttlingDeploymentService fails each RPC with the configured error
+// until it has been called failures times.
+type throttlingDeploymentService struct {
+	deploymentv1alpha1connect.UnimplementedDeploymentServiceHandler
+
+	code       connect.Code
+	retryAfter string
+	failures   int32
+	calls      atomic.Int32
+}
+
+func (s *throttlingDeploymentService) fail() error {
+	if s.calls.Add(1) > s.failures {
+		return nil
+	}
+
+	err := connect.NewError(s.code, errors.New("try again later"))
+	err.Meta().Set("Retry-After", s.retryAfter)
+
+	return err
+}
+
+func (s *throttlingDeploymentService) GetDeployment(ctx context.Context, req *connect.Request[deploymentv1alpha1.GetDeploymentRequest]) (*connect.Response[deploymentv1alpha1.GetDeploymentResponse], error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&deploymentv1alpha1.GetDeploymentResponse{}), nil
+}
+
+func (s *throttlingDeploymentService) CreateDNSRecord(ctx context.Context, req *connect.Request[deploymentv1alpha1.CreateDNSRecordRequest]) (*connect.Response[deploymentv1alpha1.CreateDNSRecordResponse], error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+
+	return connect.NewResponse(&deploymentv1alpha1.CreateDNSRecordResponse{}), nil
+}
+
+func newThrottlingClient(t *testing.T, svc *throttlingDeploymentService) deploymentv1alpha1connect.DeploymentServiceClient {
+	t.Helper()
+
+	_, handler := deploymentv1alpha1connect.NewDeploymentServiceHandler(svc)
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return deploymentv1alpha1connect.NewDeploymentServiceClient(server.Client(), server.URL, connect.WithInterceptors(&retryInterceptor{maxRetries: 3}))
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	t.Run("retries throttled reads", func(t *testing.T) {
+		svc := &throttlingDeploymentService{code: connect.CodeResourceExhausted, retryAfter: "0", failures: 2}
+		client := newThrottlingClient(t, svc)
+
+		ctx, stats := WithThrottleStats(context.Background())
+
+		_, err := client.GetDeployment(ctx, connect.NewRequest(&deploymentv1alpha1.GetDeploymentRequest{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := svc.calls.Load(); got != 3 {
+			t.Errorf("expected 3 attempts, got %d", got)
+		}
+
+		if got := stats.Count(); got != 2 {
+			t.Errorf("expected 2 throttled attempts to be recorded, got %d", got)
+		}
+	})
+
+	t.Run("gives up when asked to wait too long", func(t *testing.T) {
+		svc := &throttlingDeploymentService{code: connect.CodeResourceExhausted, retryAfter: "120", failures: 1}
+		client := newThrottlingClient(t, svc)
+
+		_, err := client.GetDeployment(context.Background(), connect.NewRequest(&deploymentv1alpha1.GetDeploymentRequest{}))
+		if connect.CodeOf(err) != connect.CodeResourceExhausted {
+			t.Fatalf("expected a ResourceExhausted error, got %v", err)
+		}
+
+		if got := svc.calls.Load(); got != 1 {
+			t.Errorf("expected 1 attempt, got %d", got)
+		}
+	})
+
+	t.Run("does not retry mutations", func(t *testing.T) {
+		svc := &throttlingDeploymentService{code: connect.CodeUnavailable, retryAfter: "0", failures: 1}
+		client := newThrottlingClient(t, svc)
+
+		_, err := client.CreateDNSRecord(context.Background(), connect.NewRequest(&deploymentv1alpha1.CreateDNSRecordRequest{}))
+		if connect.CodeOf(err) != connect.CodeUnavailable {
+			t.Fatalf("expected an Unavailable error, got %v", err)
+		}
+
+		if got := svc.calls.Load(); got != 1 {
+			t.Errorf("expected 1 attempt, got %d", got)
+		}
+	})
+
+	t.Run("retries throttled mutations", func(t *testing.T) {
+		svc := &throttlingDeploymentService{code: connect.CodeResourceExhausted, retryAfter: "0", failures: 1}
+		client := newThrottlingClient(t, svc)
+
+		ctx, stats := WithThrottleStats(context.Background())
+
+		_, err := client.CreateDNSRecord(ctx, connect.NewRequest(&deploymentv1alpha1.CreateDNSRecordRequest{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := svc.calls.Load(); got != 2 {
+			t.Errorf("expected 2 attempts, got %d", got)
+		}
+
+		if got := stats.Count(); got != 1 {
+			t.Errorf("expected 1 throttled attempt to be recorded, got %d", got)
+		}
+	})
+
+	t.Run("does not retry throttled mutations without Retry-After", func(t *testing.T) {
+		svc := &throttlingDeploymentService{code: connect.CodeResourceExhausted, failures: 1}
+		client := newThrottlingClient(t, svc)
+
+		_, err := client.CreateDNSRecord(context.Background(), connect.NewRequest(&deploymentv1alpha1.CreateDNSRecordRequest{}))
+		if connect.CodeOf(err) != connect.CodeResourceExhausted {
+			t.Fatalf("expected a ResourceExhausted error, got %v", err)
+		}
+
+		if got := svc.calls.Load(); got != 1 {
+			t.Errorf("expected 1 attempt, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package deploymeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// DefaultRequestTimeout is the time allowed for each request
+// if Opts.RequestTimeout is not provided.
+const DefaultRequestTimeout = 60 * time.Second
+
+// timeoutInterceptor bounds each unary call by a deadline. When the deadline
+// is exceeded, the returned error describes which timeout applied and how
+// long the call ran, so that it can be told apart from a network failure.
+type timeoutInterceptor struct {
+	timeout time.Duration
+	hint    string
+}
+
+func (i *timeoutInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		callCtx, cancel := context.WithTimeout(ctx, i.timeout)
+		defer cancel()
+
+		start := time.Now()
+		res, err := next(callCtx, req)
+		if err == nil {
+			return res, nil
+		}
+
+		// only annotate the error if our deadline fired, rather than
+		// a deadline or cancellation coming from the caller. The deadline
+		// is sent to the Factory, whose DeadlineExceeded response may
+		// arrive just before our own context expires.
+		deadline, _ := callCtx.Deadline()
+		callerDeadline, hasCallerDeadline := ctx.Deadline()
+		ours := !hasCallerDeadline || deadline.Before(callerDeadline)
+
+		if ctx.Err() == nil && ours && (errors.Is(callCtx.Err(), context.DeadlineExceeded) || connect.CodeOf(err) == connect.CodeDeadlineExceeded) {
+			msg := fmt.Sprintf("%s did not complete within the %s request timeout (ran for %s)", req.Spec().Procedure, i.timeout, time.Since(start).Round(time.Millisecond))
+			if i.hint != "" {
+				msg += ". " + i.hint
+			}
+
+			return nil, connect.NewError(connect.CodeDeadlineExceeded, errors.New(msg))
+		}
+
+		return res, err
+	}
+}
+
+func (i *timeoutInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *timeoutInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}
@@ -0,0 +1,75 @@
+package deploymeta
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	deploymentv1alpha1 "github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1"
+	"github.com/common-fate/sdk/gen/commonfate/factory/deployment/v1alpha1/deploymentv1alpha1connect"
+)
+
+// blockingDeploymentService doesn't respond until the request is abandoned.
+type blockingDeploymentService struct {
+	deploymentv1alpha1connect.UnimplementedDeploymentServiceHandler
+}
+
+func (s *blockingDeploymentService) GetDeployment(ctx context.Context, req *connect.Request[deploymentv1alpha1.GetDeploymentRequest]) (*connect.Response[deploymentv1alpha1.GetDeploymentResponse], error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestTimeoutInterceptor(t *testing.T) {
+	_, handler := deploymentv1alpha1connect.NewDeploymentServiceHandler(&blockingDeploymentService{})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	client := deploymentv1alpha1connect.NewDeploymentServiceClient(server.Client(), server.URL, connect.WithInterceptors(&timeoutInterceptor{
+		timeout: 50 * time.Millisecond,
+		hint:    "Raise the timeout",
+	}))
+
+	t.Run("request timeout", func(t *testing.T) {
+		_, err := client.GetDeployment(context.Background(), connect.NewRequest(&deploymentv1alpha1.GetDeploymentRequest{}))
+		if connect.CodeOf(err) != connect.CodeDeadlineExceeded {
+			t.Fatalf("expected a DeadlineExceeded error, got %v", err)
+		}
+
+		for _, want := range []string{deploymentv1alpha1connect.DeploymentServiceGetDeploymentProcedure, "50ms request timeout", "Raise the timeout"} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("expected the error to contain %q, got %v", want, err)
+			}
+		}
+	})
+
+	t.Run("caller deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetDeployment(ctx, connect.NewRequest(&deploymentv1alpha1.GetDeploymentRequest{}))
+		if connect.CodeOf(err) != connect.CodeDeadlineExceeded {
+			t.Fatalf("expected a DeadlineExceeded error, got %v", err)
+		}
+
+		if strings.Contains(err.Error(), "request timeout") {
+			t.Errorf("expected the caller's deadline not to be annotated, got %v", err)
+		}
+	})
+
+	t.Run("caller cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		_, err := client.GetDeployment(ctx, connect.NewRequest(&deploymentv1alpha1.GetDeploymentRequest{}))
+		if connect.CodeOf(err) != connect.CodeCanceled {
+			t.Fatalf("expected a Canceled error, got %v", err)
+		}
+
+		if strings.Contains(err.Error(), "request timeout") {
+			t.Errorf("expected the caller's cancellation not to be annotated, got %v", err)
+		}
+	})
+}
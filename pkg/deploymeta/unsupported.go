@@ -0,0 +1,43 @@
+package deploymeta
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+)
+
+// unsupportedInterceptor rewrites Unimplemented errors, which older and
+// self-hosted Factory builds return for RPCs they don't have, into an error
+// naming the RPC and the Factory it was sent to. The error code is kept so
+// that callers can still detect the condition.
+type unsupportedInterceptor struct {
+	baseURL string
+}
+
+func (i *unsupportedInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		res, err := next(ctx, req)
+		if connect.CodeOf(err) != connect.CodeUnimplemented {
+			return res, err
+		}
+
+		msg := fmt.Sprintf("%s is not supported by the Factory at %s, which may be an older or self-hosted build", req.Spec().Procedure, i.baseURL)
+
+		var connectErr *connect.Error
+		if errors.As(err, &connectErr) && connectErr.Message() != "" {
+			msg += ": " + connectErr.Message()
+		}
+
+		return nil, connect.NewError(connect.CodeUnimplemented, errors.New(msg))
+	}
+}
+
+func (i *unsupportedInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *unsupportedInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return next
+}